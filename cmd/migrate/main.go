@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/g-stro/content-management-service/database"
+	"github.com/g-stro/content-management-service/database/migrations"
+	"github.com/g-stro/content-management-service/internal/migrate"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+const usage = `Usage: migrate <command> [arguments]
+
+Commands:
+  up              Apply all pending migrations
+  down [n]        Revert the last n applied migrations (default 1)
+  status          List migrations and when they were applied
+  goto <version>  Migrate up or down to a version, 0 reverts every migration
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Connect to database
+	conn, err := database.NewConnection()
+	if err != nil {
+		slog.Error("failed to establish database connection", "error", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	fsys, err := migrations.ForDriver(conn.Driver)
+	if err != nil {
+		slog.Error("failed to load migrations", "error", err)
+		os.Exit(1)
+	}
+	migrator, err := migrate.New(conn.DB, conn.Driver, fsys)
+	if err != nil {
+		slog.Error("failed to load migrations", "error", err)
+		os.Exit(1)
+	}
+
+	if err := run(migrator, flag.Arg(0), flag.Args()[1:]); err != nil {
+		slog.Error("migration failed", "command", flag.Arg(0), "error", err)
+		conn.Close()
+		os.Exit(1)
+	}
+}
+
+func run(migrator *migrate.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		n, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", n)
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			var err error
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of migrations %q", args[0])
+			}
+		}
+		n, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s)\n", n)
+	case "goto":
+		if len(args) != 1 {
+			return fmt.Errorf("goto requires a version")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		n, err := migrator.Goto(version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ran %d migration(s), now at version %d\n", n, version)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	return nil
+}
@@ -1,35 +1,193 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/g-stro/content-management-service/database"
+	"github.com/g-stro/content-management-service/database/migrations"
+	"github.com/g-stro/content-management-service/internal/health"
 	"github.com/g-stro/content-management-service/internal/http/handler"
 	"github.com/g-stro/content-management-service/internal/http/middleware"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"github.com/g-stro/content-management-service/internal/migrate"
 	"github.com/g-stro/content-management-service/internal/repository"
+	"github.com/g-stro/content-management-service/internal/scheduler"
 	"github.com/g-stro/content-management-service/internal/service"
+	"github.com/g-stro/content-management-service/internal/tracing"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
+// Defaults of the server timeouts, each configurable through its environment variable
+const (
+	defaultReadHeaderTimeout = 5 * time.Second  // SERVER_READ_HEADER_TIMEOUT
+	defaultReadTimeout       = 15 * time.Second // SERVER_READ_TIMEOUT
+	defaultWriteTimeout      = 30 * time.Second // SERVER_WRITE_TIMEOUT
+	defaultIdleTimeout       = 60 * time.Second // SERVER_IDLE_TIMEOUT
+	defaultShutdownTimeout   = 20 * time.Second // SHUTDOWN_TIMEOUT
+)
+
+// placeholderEditorAPIKey is the example key once shipped in .env.example. It is publicly known, so it is refused
+// rather than granting editor access.
+const placeholderEditorAPIKey = "change-me"
+
 func main() {
+	if err := run(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// run starts the service and blocks until it fails or receives SIGINT or SIGTERM. On a signal, in-flight requests
+// are drained within SHUTDOWN_TIMEOUT, the scheduler is stopped and the database connection is closed last.
+func run() error {
+	autoMigrate := flag.Bool("migrate", false, "apply pending database migrations before starting")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Configure logging first, so that everything after it logs in the configured format
+	logger, err := newLogger()
+	if err != nil {
+		slog.Error("invalid logging config", "error", err)
+		return err
+	}
+	slog.SetDefault(logger)
+
 	// Load configs
 	port := os.Getenv("SERVICE_PORT")
 	if port == "" {
 		port = "8080"
 	}
+	schedulerInterval, err := durationEnv("SCHEDULER_INTERVAL", 30*time.Second)
+	if err != nil {
+		slog.Error("invalid scheduler config", "error", err)
+		return err
+	}
+	editorAPIKeys, err := editorAPIKeysEnv()
+	if err != nil {
+		slog.Error("invalid editor config", "error", err)
+		return err
+	}
+	if len(editorAPIKeys) == 0 {
+		slog.Warn("neither EDITOR_API_KEY nor EDITOR_API_KEYS is set, editor access is disabled")
+	}
+	server := &http.Server{Addr: ":" + port}
+	for _, timeout := range []struct {
+		env    string
+		target *time.Duration
+		def    time.Duration
+	}{
+		{"SERVER_READ_HEADER_TIMEOUT", &server.ReadHeaderTimeout, defaultReadHeaderTimeout},
+		{"SERVER_READ_TIMEOUT", &server.ReadTimeout, defaultReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", &server.WriteTimeout, defaultWriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", &server.IdleTimeout, defaultIdleTimeout},
+	} {
+		if *timeout.target, err = durationEnv(timeout.env, timeout.def); err != nil {
+			slog.Error("invalid server config", "error", err)
+			return err
+		}
+	}
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if err != nil {
+		slog.Error("invalid server config", "error", err)
+		return err
+	}
+	dbWaitTimeout, err := nonNegativeDurationEnv("DB_WAIT_TIMEOUT", 0)
+	if err != nil {
+		slog.Error("invalid database config", "error", err)
+		return err
+	}
 
-	// Connect to database
-	conn, err := database.NewConnection()
+	exporter, err := traceExporter()
 	if err != nil {
-		slog.Error("failed to establish database connection", "error", err)
-		os.Exit(1)
+		slog.Error("invalid tracing config", "error", err)
+		return err
 	}
-	defer conn.Close()
+	if exporter != nil {
+		defer exporter.Close()
+	}
+
+	checker := health.NewChecker(health.DefaultTimeout)
+	registry := metrics.NewRegistry()
 
 	// Create repository
-	contentRepo := repository.NewPostgresContentRepository(conn)
+	var contentRepo repository.ContentRepository
+	dbSystem := os.Getenv("DB_DRIVER")
+	if dbSystem == "memory" {
+		slog.Warn("using in-memory storage, all data is lost when the service stops")
+		contentRepo = repository.NewInMemoryContentRepository()
+	} else {
+		// Connect to database
+		conn, err := database.NewConnection()
+		if err != nil {
+			slog.Error("failed to establish database connection", "error", err)
+			return err
+		}
+		// Deferred first, so the connection is closed after everything that uses it has stopped
+		defer func() {
+			conn.Close()
+			slog.Info("database connection closed")
+		}()
+
+		// With a DB_WAIT_TIMEOUT of 0, the default, the database must be reachable on the first attempt
+		if err := conn.WaitUntilReachable(ctx, dbWaitTimeout); err != nil {
+			slog.Error("failed to reach database", "error", err)
+			return err
+		}
+
+		fsys, err := migrations.ForDriver(conn.Driver)
+		if err != nil {
+			slog.Error("failed to load migrations", "error", err)
+			return err
+		}
+		migrator, err := migrate.New(conn.DB, conn.Driver, fsys)
+		if err != nil {
+			slog.Error("failed to load migrations", "error", err)
+			return err
+		}
+		if *autoMigrate {
+			if _, err := migrator.Up(); err != nil {
+				slog.Error("failed to migrate database", "error", err)
+				return err
+			}
+		}
+
+		checker.Add("database", conn.Ping)
+		checker.Add("migrations", pendingMigrationsCheck(migrator))
+		metrics.RegisterDBStats(registry, conn.DB)
+
+		dbSystem = conn.Driver
+		if conn.Driver == database.DriverSQLite {
+			contentRepo = repository.NewSQLiteContentRepository(conn)
+		} else {
+			contentRepo = repository.NewPostgresContentRepository(conn)
+		}
+	}
+	contentRepo = repository.NewInstrumentedContentRepository(contentRepo,
+		repository.NewQueryDurationHistogram(registry))
+	if exporter != nil {
+		contentRepo = repository.NewTracedContentRepository(contentRepo, dbSystem)
+	}
 	// Create service
-	contentService := service.NewContentService(contentRepo, nil)
+	contentService := service.NewContentService(contentRepo, nil, registry)
+	// Start publishing scheduler
+	publishScheduler := scheduler.NewScheduler(contentService, schedulerInterval)
+	publishScheduler.Start()
+	defer func() {
+		publishScheduler.Stop()
+		slog.Info("scheduler stopped")
+	}()
+	checker.Add("scheduler", publishScheduler.Check)
 	// Create handler
 	contentHandler := handler.NewContentHandler(contentService)
 
@@ -37,13 +195,168 @@ func main() {
 	mux := http.NewServeMux()
 	// Register routes
 	contentHandler.RegisterRoutes(mux)
+	handler.NewHealthHandler(checker).RegisterRoutes(mux)
+	mux.Handle("/metrics", registry.Handler())
 	// Setup middleware
-	httpHandler := middleware.CorsMiddleware(mux)
+	server.Handler = middleware.CorsMiddleware(mux)
+	// Probes and scrapes are polled frequently, so they are only logged when they fail
+	server.Handler = middleware.AccessLogMiddleware(logger, "/healthz", "/readyz", "/metrics")(server.Handler)
+	// Authenticate editors before the access log, so that it records who made each request
+	server.Handler = middleware.EditorMiddleware(editorAPIKeys)(server.Handler)
+	if exporter != nil {
+		server.Handler = middleware.TracingMiddleware(tracing.NewTracer(exporter), mux)(server.Handler)
+	}
+	server.Handler = middleware.MetricsMiddleware(registry, mux)(server.Handler)
 
-	// Create HTTP server
-	err = http.ListenAndServe(":"+port, httpHandler)
-	if err != nil {
+	// Start HTTP server
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		slog.Error("failed to start server", "error", err)
-		os.Exit(1)
+		return err
+	case <-ctx.Done():
+		stop() // A second signal terminates the process immediately
+	}
+
+	// Stop accepting connections and wait for in-flight requests to complete
+	slog.Info("shutting down server", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed to drain connections before the shutdown deadline", "error", err)
+		_ = server.Close()
+		return err
+	}
+	if err := <-serverErr; !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("server stopped unexpectedly", "error", err)
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
+
+// pendingMigrationsCheck reports the database as not ready while it has migrations that have not been applied
+func pendingMigrationsCheck(migrator *migrate.Migrator) health.Check {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}
+}
+
+// newLogger returns the logger configured by LOG_LEVEL (default info), LOG_FORMAT (text or json, default text),
+// LOG_PAYLOADS (default false) and LOG_REDACT_FIELDS, a comma-separated list of field and header names to redact in
+// addition to the defaults
+func newLogger() (*slog.Logger, error) {
+	var level slog.Level // Info
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q", v)
+		}
+	}
+
+	var payloads bool
+	if v := os.Getenv("LOG_PAYLOADS"); v != "" {
+		var err error
+		if payloads, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid LOG_PAYLOADS %q", v)
+		}
+	}
+
+	var redactKeys []string
+	if v := os.Getenv("LOG_REDACT_FIELDS"); v != "" {
+		redactKeys = strings.Split(v, ",")
+	}
+
+	return logging.New(os.Stderr, logging.Options{
+		Level:      level,
+		Format:     os.Getenv("LOG_FORMAT"),
+		Payloads:   payloads,
+		RedactKeys: redactKeys,
+	})
+}
+
+// traceExporter returns the span exporter selected by TRACING_EXPORTER, or nil if tracing is disabled. Spans are
+// written as JSON lines to stdout or, with the file exporter, to TRACING_FILE.
+func traceExporter() (*tracing.WriterExporter, error) {
+	switch v := os.Getenv("TRACING_EXPORTER"); v {
+	case "", "none":
+		return nil, nil
+	case "stdout":
+		return tracing.NewWriterExporter(os.Stdout), nil
+	case "file":
+		path := os.Getenv("TRACING_FILE")
+		if path == "" {
+			path = "traces.jsonl" // Default
+		}
+		return tracing.NewFileExporter(path)
+	default:
+		return nil, fmt.Errorf("invalid TRACING_EXPORTER %q", v)
+	}
+}
+
+// editorAPIKeysEnv returns the API keys by editor name set in EDITOR_API_KEYS, plus the key set in EDITOR_API_KEY,
+// which belongs to the editor named "editor"
+func editorAPIKeysEnv() (map[string]string, error) {
+	apiKeys, err := middleware.ParseEditorAPIKeys(os.Getenv("EDITOR_API_KEYS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EDITOR_API_KEYS: %w", err)
+	}
+	for name, k := range apiKeys {
+		if k == placeholderEditorAPIKey {
+			return nil, fmt.Errorf("EDITOR_API_KEYS editor %q uses the example key %q", name, placeholderEditorAPIKey)
+		}
+	}
+
+	key := os.Getenv("EDITOR_API_KEY")
+	if key == "" {
+		return apiKeys, nil
+	}
+	if key == placeholderEditorAPIKey {
+		return nil, fmt.Errorf("EDITOR_API_KEY is the example key %q", placeholderEditorAPIKey)
+	}
+	for name, k := range apiKeys {
+		if name == middleware.DefaultEditorName || k == key {
+			return nil, fmt.Errorf("EDITOR_API_KEYS editor %q conflicts with EDITOR_API_KEY", name)
+		}
+	}
+	apiKeys[middleware.DefaultEditorName] = key
+	return apiKeys, nil
+}
+
+// durationEnv returns the positive duration set in an environment variable, or def if the variable is not set
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
+	}
+	return d, nil
+}
+
+// nonNegativeDurationEnv returns the duration set in an environment variable, or def if the variable is not set.
+// Unlike durationEnv it accepts 0, for settings that 0 turns off.
+func nonNegativeDurationEnv(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
 	}
+	return d, nil
 }
@@ -0,0 +1,20 @@
+// Package migrations embeds the versioned schema migrations of the service, one directory per database driver.
+// Each migration is a pair of NNNN_name.up.sql and NNNN_name.down.sql files, applied in version order.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// ForDriver returns the migrations written for a database driver
+func ForDriver(driver string) (fs.FS, error) {
+	if _, err := fs.Stat(files, driver); err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+	return fs.Sub(files, driver)
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "content_details";
+DROP TABLE IF EXISTS "content_type";
+DROP TABLE IF EXISTS "content";
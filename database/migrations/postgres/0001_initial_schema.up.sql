@@ -1,6 +1,6 @@
-SET TIMEZONE = 'UTC';
-
-CREATE TABLE "content"
+-- Baseline schema, identical to the former sql/sql.sql. Objects are created only if missing, so databases initialised
+-- from that file can adopt migrations; the columns and tables added since are created by later migrations.
+CREATE TABLE IF NOT EXISTS "content"
 (
     "id"                 SERIAL PRIMARY KEY,
     "name" VARCHAR(255),
@@ -9,13 +9,13 @@ CREATE TABLE "content"
     "last_modified_date" TIMESTAMP
 );
 
-CREATE TABLE "content_type"
+CREATE TABLE IF NOT EXISTS "content_type"
 (
     "id"   SERIAL PRIMARY KEY,
     "name" VARCHAR(50)
 );
 
-CREATE TABLE "content_details"
+CREATE TABLE IF NOT EXISTS "content_details"
 (
     "id"              SERIAL PRIMARY KEY,
     "content_id"      INTEGER,
@@ -0,0 +1,10 @@
+-- Details are ordered by position within their content. Existing details keep the order in which they were created.
+ALTER TABLE "content_details" ADD COLUMN IF NOT EXISTS "position" INTEGER NOT NULL DEFAULT 0;
+
+UPDATE "content_details" cd
+SET "position" = ordered."position"
+FROM (SELECT "id", ROW_NUMBER() OVER (PARTITION BY "content_id" ORDER BY "id") - 1 AS "position"
+      FROM "content_details") ordered
+WHERE cd."id" = ordered."id";
+
+CREATE INDEX IF NOT EXISTS "content_details_content_id_position_idx" ON "content_details" ("content_id", "position");
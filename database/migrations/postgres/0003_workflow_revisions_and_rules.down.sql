@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS "content_unpublish_at_idx";
+DROP INDEX IF EXISTS "content_publish_at_idx";
+DROP INDEX IF EXISTS "content_status_idx";
+DROP INDEX IF EXISTS "content_name_idx";
+DROP INDEX IF EXISTS "content_last_modified_date_idx";
+DROP INDEX IF EXISTS "content_creation_date_idx";
+
+DROP TABLE IF EXISTS "content_revision";
+
+ALTER TABLE "content_type" DROP CONSTRAINT IF EXISTS "content_type_name_key";
+DROP INDEX IF EXISTS "content_type_name_key";
+ALTER TABLE "content_type" DROP COLUMN IF EXISTS "rules";
+ALTER TABLE "content_type" ALTER COLUMN "name" DROP NOT NULL;
+
+ALTER TABLE "content" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "content" DROP COLUMN IF EXISTS "unpublish_at";
+ALTER TABLE "content" DROP COLUMN IF EXISTS "publish_at";
+ALTER TABLE "content" DROP COLUMN IF EXISTS "last_modified_by";
+ALTER TABLE "content" DROP COLUMN IF EXISTS "status";
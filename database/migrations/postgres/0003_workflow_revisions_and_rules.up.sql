@@ -0,0 +1,45 @@
+-- Editorial workflow, scheduling, soft delete, revision history and content type rules. Every change is skipped if
+-- it already exists, so this also applies to databases created from a later version of sql/sql.sql, which had them.
+
+-- Content that existed before the workflow was public, so it is published; new content starts as a draft
+ALTER TABLE "content" ADD COLUMN IF NOT EXISTS "status" VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK ("status" IN ('draft', 'review', 'published', 'archived'));
+ALTER TABLE "content" ALTER COLUMN "status" SET DEFAULT 'draft';
+ALTER TABLE "content" ADD COLUMN IF NOT EXISTS "last_modified_by" VARCHAR(255);
+ALTER TABLE "content" ADD COLUMN IF NOT EXISTS "publish_at" TIMESTAMP;
+ALTER TABLE "content" ADD COLUMN IF NOT EXISTS "unpublish_at" TIMESTAMP;
+ALTER TABLE "content" ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMP;
+
+ALTER TABLE "content_type" ALTER COLUMN "name" SET NOT NULL;
+ALTER TABLE "content_type" ADD COLUMN IF NOT EXISTS "rules" JSONB NOT NULL DEFAULT '{}';
+-- Named like the constraint of a UNIQUE column, so it is skipped where the constraint exists
+CREATE UNIQUE INDEX IF NOT EXISTS "content_type_name_key" ON "content_type" ("name");
+
+CREATE TABLE IF NOT EXISTS "content_revision"
+(
+    "id"         SERIAL PRIMARY KEY,
+    "content_id" INTEGER   NOT NULL,
+    "revision"   INTEGER   NOT NULL,
+    "snapshot"   JSONB     NOT NULL,
+    "author"     VARCHAR(255),
+    "created_at" TIMESTAMP NOT NULL,
+    FOREIGN KEY ("content_id") REFERENCES "content" ("id"),
+    UNIQUE ("content_id", "revision")
+);
+
+CREATE INDEX IF NOT EXISTS "content_creation_date_idx" ON "content" ("creation_date", "id");
+CREATE INDEX IF NOT EXISTS "content_last_modified_date_idx" ON "content" ("last_modified_date", "id");
+CREATE INDEX IF NOT EXISTS "content_name_idx" ON "content" ("name", "id");
+CREATE INDEX IF NOT EXISTS "content_status_idx" ON "content" ("status");
+CREATE INDEX IF NOT EXISTS "content_publish_at_idx" ON "content" ("publish_at") WHERE "publish_at" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "content_unpublish_at_idx" ON "content" ("unpublish_at") WHERE "unpublish_at" IS NOT NULL;
+
+-- Give the seeded content types their rules unless they have been changed
+UPDATE content_type SET rules = '{"max_length": 10000}' WHERE id = 1 AND name = 'text' AND rules = '{}';
+UPDATE content_type SET rules = '{"format": "url", "mime_types": ["image/*"]}'
+WHERE id = 2 AND name = 'image' AND rules = '{}';
+UPDATE content_type SET rules = '{"format": "url", "mime_types": ["video/*"]}'
+WHERE id = 3 AND name = 'video' AND rules = '{}';
+
+-- Keep the sequence ahead of the seeded IDs so new content types can be created
+SELECT setval(pg_get_serial_sequence('content_type', 'id'), (SELECT MAX("id") FROM content_type));
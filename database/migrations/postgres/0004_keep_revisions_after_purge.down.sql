@@ -0,0 +1,3 @@
+-- The revisions of purged content are kept, so the constraint only applies to new revisions
+ALTER TABLE "content_revision"
+    ADD CONSTRAINT "content_revision_content_id_fkey" FOREIGN KEY ("content_id") REFERENCES "content" ("id") NOT VALID;
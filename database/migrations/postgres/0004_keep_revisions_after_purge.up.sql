@@ -0,0 +1,3 @@
+-- Revisions outlive their content, so that the history of purged content can still be audited. Content IDs come
+-- from a sequence and are never reused, so the revisions of purged content cannot be mistaken for those of new content.
+ALTER TABLE "content_revision" DROP CONSTRAINT IF EXISTS "content_revision_content_id_fkey";
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "content_revision";
+DROP TABLE IF EXISTS "content_details";
+DROP TABLE IF EXISTS "content_type";
+DROP TABLE IF EXISTS "content";
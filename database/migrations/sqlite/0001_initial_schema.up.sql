@@ -0,0 +1,59 @@
+-- Baseline schema for SQLite. Times are stored as UTC text, which sorts chronologically, and JSON as text.
+CREATE TABLE IF NOT EXISTS "content"
+(
+    "id"                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    "name"               TEXT,
+    "description"        TEXT,
+    "status"             TEXT NOT NULL DEFAULT 'draft'
+        CHECK ("status" IN ('draft', 'review', 'published', 'archived')),
+    "creation_date"      TIMESTAMP,
+    "last_modified_date" TIMESTAMP,
+    "last_modified_by"   TEXT,
+    "publish_at"         TIMESTAMP,
+    "unpublish_at"       TIMESTAMP,
+    "deleted_at"         TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "content_type"
+(
+    "id"    INTEGER PRIMARY KEY AUTOINCREMENT,
+    "name"  TEXT NOT NULL UNIQUE,
+    "rules" TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS "content_details"
+(
+    "id"              INTEGER PRIMARY KEY AUTOINCREMENT,
+    "content_id"      INTEGER,
+    "content_type_id" INTEGER,
+    "value"           TEXT,
+    FOREIGN KEY ("content_id") REFERENCES "content" ("id"),
+    FOREIGN KEY ("content_type_id") REFERENCES "content_type" ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "content_revision"
+(
+    "id"         INTEGER PRIMARY KEY AUTOINCREMENT,
+    "content_id" INTEGER   NOT NULL,
+    "revision"   INTEGER   NOT NULL,
+    "snapshot"   TEXT      NOT NULL,
+    "author"     TEXT,
+    "created_at" TIMESTAMP NOT NULL,
+    FOREIGN KEY ("content_id") REFERENCES "content" ("id"),
+    UNIQUE ("content_id", "revision")
+);
+
+CREATE INDEX IF NOT EXISTS "content_creation_date_idx" ON "content" ("creation_date", "id");
+CREATE INDEX IF NOT EXISTS "content_last_modified_date_idx" ON "content" ("last_modified_date", "id");
+CREATE INDEX IF NOT EXISTS "content_name_idx" ON "content" ("name", "id");
+CREATE INDEX IF NOT EXISTS "content_status_idx" ON "content" ("status");
+CREATE INDEX IF NOT EXISTS "content_publish_at_idx" ON "content" ("publish_at") WHERE "publish_at" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "content_unpublish_at_idx" ON "content" ("unpublish_at") WHERE "unpublish_at" IS NOT NULL;
+CREATE INDEX IF NOT EXISTS "content_details_content_id_idx" ON "content_details" ("content_id");
+
+-- AUTOINCREMENT continues after the highest seeded ID
+INSERT OR IGNORE INTO content_type (id, name, rules)
+VALUES
+    (1, 'text', '{"max_length": 10000}'),
+    (2, 'image', '{"format": "url", "mime_types": ["image/*"]}'),
+    (3, 'video', '{"format": "url", "mime_types": ["video/*"]}');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "content_details_content_id_position_idx";
+ALTER TABLE "content_details" DROP COLUMN "position";
@@ -0,0 +1,9 @@
+-- Details are ordered by position within their content. Existing details keep the order in which they were created.
+ALTER TABLE "content_details" ADD COLUMN "position" INTEGER NOT NULL DEFAULT 0;
+
+UPDATE "content_details"
+SET "position" = (SELECT COUNT(*)
+                  FROM "content_details" p
+                  WHERE p."content_id" = "content_details"."content_id" AND p."id" < "content_details"."id");
+
+CREATE INDEX IF NOT EXISTS "content_details_content_id_position_idx" ON "content_details" ("content_id", "position");
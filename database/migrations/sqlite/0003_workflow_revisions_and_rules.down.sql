@@ -0,0 +1,2 @@
+-- Intentionally empty, see 0003_workflow_revisions_and_rules.up.sql
+SELECT 1;
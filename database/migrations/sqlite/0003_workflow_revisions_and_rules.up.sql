@@ -0,0 +1,4 @@
+-- Intentionally empty. This version moved the Postgres workflow, revision and content type rule schema out of the
+-- baseline, so that databases created from sql/sql.sql can adopt migrations. SQLite databases have always been
+-- created by migration 0001, which already includes that schema.
+SELECT 1;
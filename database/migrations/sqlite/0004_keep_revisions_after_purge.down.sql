@@ -0,0 +1,20 @@
+-- SQLite enforces foreign keys on every insert, so the revisions of purged content cannot be kept
+CREATE TABLE "content_revision_old"
+(
+    "id"         INTEGER PRIMARY KEY AUTOINCREMENT,
+    "content_id" INTEGER   NOT NULL,
+    "revision"   INTEGER   NOT NULL,
+    "snapshot"   TEXT      NOT NULL,
+    "author"     TEXT,
+    "created_at" TIMESTAMP NOT NULL,
+    FOREIGN KEY ("content_id") REFERENCES "content" ("id"),
+    UNIQUE ("content_id", "revision")
+);
+
+INSERT INTO "content_revision_old" ("id", "content_id", "revision", "snapshot", "author", "created_at")
+SELECT "id", "content_id", "revision", "snapshot", "author", "created_at"
+FROM "content_revision"
+WHERE "content_id" IN (SELECT "id" FROM "content");
+
+DROP TABLE "content_revision";
+ALTER TABLE "content_revision_old" RENAME TO "content_revision";
@@ -0,0 +1,20 @@
+-- Revisions outlive their content, so that the history of purged content can still be audited. Content IDs are
+-- AUTOINCREMENT and never reused, so the revisions of purged content cannot be mistaken for those of new content.
+-- SQLite cannot drop a foreign key, so the table is rebuilt without it.
+CREATE TABLE "content_revision_new"
+(
+    "id"         INTEGER PRIMARY KEY AUTOINCREMENT,
+    "content_id" INTEGER   NOT NULL,
+    "revision"   INTEGER   NOT NULL,
+    "snapshot"   TEXT      NOT NULL,
+    "author"     TEXT,
+    "created_at" TIMESTAMP NOT NULL,
+    UNIQUE ("content_id", "revision")
+);
+
+INSERT INTO "content_revision_new" ("id", "content_id", "revision", "snapshot", "author", "created_at")
+SELECT "id", "content_id", "revision", "snapshot", "author", "created_at"
+FROM "content_revision";
+
+DROP TABLE "content_revision";
+ALTER TABLE "content_revision_new" RENAME TO "content_revision";
@@ -1,22 +1,102 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	_ "github.com/lib/pq"
+	"log/slog"
 	"os"
+	"time"
 )
 
+// Database drivers selectable with DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// DefaultQueryTimeout bounds each repository operation when DB_QUERY_TIMEOUT is not set
+const DefaultQueryTimeout = 5 * time.Second
+
 type Connection struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Driver string
+	// QueryTimeout bounds each repository operation, including all of its queries. Zero disables the timeout.
+	QueryTimeout time.Duration
 }
 
+// NewConnection opens a connection to the database selected by DB_DRIVER, PostgreSQL by default
 func NewConnection() (*Connection, error) {
-	conn, err := sql.Open("postgres", getDSN())
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = DriverPostgres
+	}
+
+	queryTimeout := DefaultQueryTimeout
+	if v := os.Getenv("DB_QUERY_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid DB_QUERY_TIMEOUT %q", v)
+		}
+		queryTimeout = d
+	}
+
+	var dsn string
+	switch driver {
+	case DriverPostgres:
+		dsn = getDSN()
+	case DriverSQLite:
+		dsn = getSQLiteDSN()
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
+	}
+
+	conn, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
-	return &Connection{DB: conn}, nil
+	return &Connection{DB: conn, Driver: driver, QueryTimeout: queryTimeout}, nil
+}
+
+// Backoff between the attempts of WaitUntilReachable, doubling after each attempt
+const (
+	minRetryBackoff = 250 * time.Millisecond
+	maxRetryBackoff = 5 * time.Second
+)
+
+// Ping checks that the database is reachable within the query timeout
+func (conn *Connection) Ping(ctx context.Context) error {
+	if conn.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conn.QueryTimeout)
+		defer cancel()
+	}
+	return conn.DB.PingContext(ctx)
+}
+
+// WaitUntilReachable pings the database until it responds, backing off exponentially between attempts. It gives up
+// once another attempt would start after the timeout, so a zero timeout makes a single attempt.
+func (conn *Connection) WaitUntilReachable(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	backoff := minRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := conn.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("database is not reachable after %d attempts: %w", attempt, err)
+		}
+
+		slog.Warn("database is not reachable, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
 }
 
 func (conn *Connection) Close() {
@@ -0,0 +1,85 @@
+//go:build !integration
+
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewConnection_QueryTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "default", expected: DefaultQueryTimeout},
+		{name: "configured", value: "250ms", expected: 250 * time.Millisecond},
+		{name: "disabled", value: "0", expected: 0},
+		{name: "negative", value: "-1s", wantErr: true},
+		{name: "invalid", value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_DRIVER", DriverSQLite)
+			t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "content.db"))
+			t.Setenv("DB_QUERY_TIMEOUT", tt.value)
+
+			conn, err := NewConnection()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewConnection() error = %v, expected error = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			if conn.QueryTimeout != tt.expected {
+				t.Errorf("NewConnection() query timeout got = %v, expected = %v", conn.QueryTimeout, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConnection_WaitUntilReachable(t *testing.T) {
+	t.Run("reachable", func(t *testing.T) {
+		t.Setenv("DB_DRIVER", DriverSQLite)
+		t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "content.db"))
+		conn, err := NewConnection()
+		if err != nil {
+			t.Fatalf("NewConnection() failed: %v", err)
+		}
+		defer conn.Close()
+
+		if err := conn.WaitUntilReachable(context.Background(), 0); err != nil {
+			t.Errorf("WaitUntilReachable() got = %v, expected = nil", err)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		// Nothing listens on port 1, so every attempt is refused
+		t.Setenv("DB_DRIVER", DriverPostgres)
+		t.Setenv("DB_HOST", "127.0.0.1")
+		t.Setenv("DB_PORT", "1")
+		t.Setenv("DB_SSL_MODE", "disable")
+		conn, err := NewConnection()
+		if err != nil {
+			t.Fatalf("NewConnection() failed: %v", err)
+		}
+		defer conn.Close()
+
+		start := time.Now()
+		err = conn.WaitUntilReachable(context.Background(), 600*time.Millisecond)
+		elapsed := time.Since(start)
+		if err == nil {
+			t.Fatal("WaitUntilReachable() got = nil, expected an error")
+		}
+		// Attempts at 0 and 250ms; the next would start after 750ms, past the timeout
+		if elapsed < minRetryBackoff || elapsed > 2*time.Second {
+			t.Errorf("WaitUntilReachable() returned after %v, expected to retry until the timeout", elapsed)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	_ "modernc.org/sqlite"
+	"net/url"
+	"os"
+)
+
+// defaultSQLitePath is the database file used when DB_PATH is not set
+const defaultSQLitePath = "content.db"
+
+// getSQLiteDSN returns the DSN of the SQLite database file at DB_PATH. Foreign keys are enforced, and transactions
+// take the write lock when they begin and wait for it when busy, so concurrent writers queue instead of failing.
+// Times are written in a format that sorts chronologically as text.
+func getSQLiteDSN() string {
+	path := os.Getenv("DB_PATH")
+	if path == "" {
+		path = defaultSQLitePath
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+	return "file:" + path + "?" + params.Encode()
+}
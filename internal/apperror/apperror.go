@@ -0,0 +1,80 @@
+// Package apperror defines the domain errors shared between the service and HTTP layers. Each error has a kind,
+// which the HTTP layer translates into a status code, and a stable machine-readable code for clients.
+package apperror
+
+// Kind classifies a domain error by how the caller should react to it
+type Kind int
+
+const (
+	KindInvalid              Kind = iota + 1 // The request is malformed
+	KindForbidden                            // The caller may not perform the operation
+	KindNotFound                             // The target of the operation does not exist
+	KindConflict                             // The operation conflicts with the current state of the target
+	KindPreconditionFailed                   // A precondition supplied by the caller no longer holds
+	KindUnsupportedMediaType                 // The request body is in a format that is not accepted
+	KindValidation                           // The request is well-formed but some of its fields are invalid
+)
+
+// FieldError describes why the value of a single request field is invalid
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error is a domain error. Errors with the same code match each other with errors.Is, so a sentinel declared with
+// New still matches an error that was created from it with WithFields.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+}
+
+// New creates a domain error of the given kind
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Invalid(code, message string) *Error {
+	return New(KindInvalid, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+func UnsupportedMediaType(code, message string) *Error {
+	return New(KindUnsupportedMediaType, code, message)
+}
+
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+// WithFields returns a copy of the error carrying the given field errors
+func (e *Error) WithFields(fields []FieldError) *Error {
+	c := *e
+	c.Fields = fields
+	return &c
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
@@ -0,0 +1,37 @@
+//go:build !integration
+
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError_Is(t *testing.T) {
+	errNotFound := NotFound("content_not_found", "content not found")
+	errValidation := Validation("validation_failed", "validation failed")
+
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{name: "same error", err: errNotFound, target: errNotFound, expected: true},
+		{name: "wrapped error", err: fmt.Errorf("%w: id 1", errNotFound), target: errNotFound, expected: true},
+		{name: "copy with fields",
+			err: errValidation.WithFields([]FieldError{{Field: "name", Message: "required"}}), target: errValidation,
+			expected: true},
+		{name: "different code", err: errNotFound, target: NotFound("revision_not_found", ""), expected: false},
+		{name: "plain error", err: errors.New("content not found"), target: errNotFound, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.expected {
+				t.Errorf("errors.Is() got = %v, expected = %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -3,14 +3,74 @@ package dto
 import "time"
 
 type Content struct {
-	ID           int       `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	CreationDate time.Time `json:"creation_date"`
-	Details      []Details `json:"details"`
+	ID               int        `json:"id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status,omitempty"`
+	CreationDate     time.Time  `json:"creation_date"`
+	LastModifiedDate time.Time  `json:"last_modified_date"`
+	LastModifiedBy   string     `json:"last_modified_by,omitempty"`
+	PublishAt        *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt      *time.Time `json:"unpublish_at,omitempty"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	Details          []Details  `json:"details"`
 }
 
 type Details struct {
 	ContentType string `json:"content_type"`
 	Value       string `json:"value"`
 }
+
+type ContentType struct {
+	ID    int        `json:"id"`
+	Name  string     `json:"name"`
+	Rules ValueRules `json:"rules"`
+}
+
+type ValueRules struct {
+	MaxLength int      `json:"max_length,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	MimeTypes []string `json:"mime_types,omitempty"`
+}
+
+// ContentQuery holds the pagination, sorting and filtering options for listing content
+type ContentQuery struct {
+	Limit         int
+	Cursor        string
+	SortBy        string
+	Descending    bool
+	Status        string
+	NamePrefix    string
+	ContentType   string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// ContentPage is a single page of content and the cursor of the page that follows it
+type ContentPage struct {
+	Content    []*Content
+	NextCursor string
+}
+
+// SearchResult is content matched by a search together with its relevance score and highlighted snippet
+type SearchResult struct {
+	Content *Content
+	Score   float64
+	Snippet string
+}
+
+// Revision is a point-in-time snapshot of content
+type Revision struct {
+	Revision  int
+	Author    string
+	CreatedAt time.Time
+	Content   *Content
+}
+
+// FieldChange describes how a single field differs between two revisions. From or To is nil when the field
+// does not exist on that side, such as a detail that was added or removed.
+type FieldChange struct {
+	Field string
+	From  *string
+	To    *string
+}
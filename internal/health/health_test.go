@@ -0,0 +1,74 @@
+//go:build !integration
+
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker_Run(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name     string
+		checks   map[string]Check
+		expected map[string]string
+		status   string
+	}{
+		{
+			name:     "no checks",
+			checks:   map[string]Check{},
+			expected: map[string]string{},
+			status:   StatusOK,
+		},
+		{
+			name:     "all checks pass",
+			checks:   map[string]Check{"database": ok, "scheduler": ok},
+			expected: map[string]string{"database": StatusOK, "scheduler": StatusOK},
+			status:   StatusOK,
+		},
+		{
+			name:     "failing check",
+			checks:   map[string]Check{"database": failing, "scheduler": ok},
+			expected: map[string]string{"database": StatusUnavailable, "scheduler": StatusOK},
+			status:   StatusUnavailable,
+		},
+		{
+			name:     "check exceeding the timeout",
+			checks:   map[string]Check{"database": slow},
+			expected: map[string]string{"database": StatusUnavailable},
+			status:   StatusUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(10 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Add(name, check)
+			}
+
+			report := checker.Run(context.Background())
+			if report.Status != tt.status {
+				t.Errorf("Run() status got = %s, expected = %s", report.Status, tt.status)
+			}
+			if len(report.Checks) != len(tt.expected) {
+				t.Fatalf("Run() checks got = %v, expected = %v", report.Checks, tt.expected)
+			}
+			for name, status := range tt.expected {
+				result := report.Checks[name]
+				if result.Status != status || (status == StatusOK) != (result.Error == "") {
+					t.Errorf("Run() check %s got = %+v, expected status = %s", name, result, status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/http/response"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) handleContentTypesRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getContentTypes(w, r)
+	case http.MethodPost:
+		h.createContentType(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleContentTypeRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getContentType(w, r)
+	case http.MethodPut:
+		h.updateContentType(w, r)
+	case http.MethodDelete:
+		h.deleteContentType(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) getContentTypes(w http.ResponseWriter, r *http.Request) {
+	contentTypes, err := h.svc.GetContentTypes(r.Context())
+	if err != nil {
+		writeError(w, r, err, "failed to retrieve content types")
+		return
+	}
+
+	contentTypesResp := make([]response.ContentType, 0)
+	for _, ct := range contentTypes {
+		contentTypesResp = append(contentTypesResp, toContentTypeResponse(ct))
+	}
+
+	resp := struct {
+		ContentTypes []response.ContentType `json:"content_types"`
+	}{
+		ContentTypes: contentTypesResp,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "content types retrieved successfully")
+}
+
+func (h *Handler) getContentType(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseContentTypeID(w, r)
+	if !ok {
+		return
+	}
+
+	contentType, err := h.svc.GetContentType(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "failed to retrieve content type")
+		return
+	}
+
+	response.HttpSuccess(w, r, toContentTypeResponse(contentType), http.StatusOK, "content type retrieved successfully")
+}
+
+func (h *Handler) createContentType(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	var req dto.ContentType
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, r, errInvalidBody, "invalid request body")
+		return
+	}
+
+	contentType, err := h.svc.CreateContentType(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err, "failed to create content type")
+		return
+	}
+
+	response.HttpSuccess(w, r, toContentTypeResponse(contentType), http.StatusCreated, "content type created successfully")
+}
+
+func (h *Handler) updateContentType(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentTypeID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ContentType
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, r, errInvalidBody, "invalid request body")
+		return
+	}
+
+	contentType, err := h.svc.UpdateContentType(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err, "failed to update content type")
+		return
+	}
+
+	response.HttpSuccess(w, r, toContentTypeResponse(contentType), http.StatusOK, "content type updated successfully")
+}
+
+func (h *Handler) deleteContentType(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentTypeID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.svc.DeleteContentType(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "failed to delete content type")
+		return
+	}
+
+	resp := struct {
+		ID int `json:"id"`
+	}{
+		ID: id,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "content type deleted successfully")
+}
+
+// parseContentTypeID extracts the content type ID path value, writing a fail response if it is invalid
+func parseContentTypeID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, r, errInvalidContentTypeID, "invalid content type ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// toContentTypeResponse converts a content type DTO into its API response representation
+func toContentTypeResponse(ct *dto.ContentType) response.ContentType {
+	return response.ContentType{
+		ID:   ct.ID,
+		Name: ct.Name,
+		Rules: response.ValueRules{
+			MaxLength: ct.Rules.MaxLength,
+			Format:    ct.Rules.Format,
+			MimeTypes: ct.Rules.MimeTypes,
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"github.com/g-stro/content-management-service/internal/apperror"
+	"github.com/g-stro/content-management-service/internal/http/response"
+	"net/http"
+)
+
+var (
+	errInvalidBody          = apperror.Invalid("invalid_request_body", "invalid request body")
+	errInvalidContentID     = apperror.Invalid("invalid_content_id", "invalid content ID")
+	errInvalidContentTypeID = apperror.Invalid("invalid_content_type_id", "invalid content type ID")
+	errInvalidRevision      = apperror.Invalid("invalid_revision", "invalid revision")
+	errInvalidQuery         = apperror.Invalid("invalid_query", "invalid query")
+	errEditorRequired       = apperror.Forbidden("editor_required", "editor access required")
+	errUnsupportedPatchType = apperror.UnsupportedMediaType("unsupported_media_type",
+		"unsupported content type, expected application/merge-patch+json")
+)
+
+// statusByKind maps each kind of domain error to the HTTP status code it is reported with
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindInvalid:              http.StatusBadRequest,
+	apperror.KindForbidden:            http.StatusForbidden,
+	apperror.KindNotFound:             http.StatusNotFound,
+	apperror.KindConflict:             http.StatusConflict,
+	apperror.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	apperror.KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	apperror.KindValidation:           http.StatusUnprocessableEntity,
+}
+
+// writeError writes the response for a failed request. Domain errors become fail responses carrying their code, with
+// a status code derived from their kind; any other error is reported as an internal server error.
+func writeError(w http.ResponseWriter, r *http.Request, err error, logMsg string) {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		response.HttpError(w, r, err, http.StatusInternalServerError, logMsg)
+		return
+	}
+
+	status, ok := statusByKind[appErr.Kind]
+	if !ok {
+		status = http.StatusBadRequest
+	}
+
+	// The full message includes any detail the error was wrapped with
+	data := response.FailDetail{
+		Code:    appErr.Code,
+		Message: err.Error(),
+	}
+	if len(appErr.Fields) > 0 {
+		data.Fields = make(map[string]string, len(appErr.Fields))
+		for _, f := range appErr.Fields {
+			if _, ok := data.Fields[f.Field]; !ok {
+				data.Fields[f.Field] = f.Message
+			}
+		}
+	}
+
+	response.HttpFail(w, r, data, status, appErr.Message)
+}
@@ -0,0 +1,91 @@
+//go:build !integration
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/apperror"
+	"github.com/g-stro/content-management-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   map[string]interface{}
+	}{
+		{
+			name:       "not found",
+			err:        service.ErrContentNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody: map[string]interface{}{
+				"status": "fail",
+				"data":   map[string]interface{}{"code": "content_not_found", "message": "content not found"},
+			},
+		},
+		{
+			name:       "wrapped conflict keeps its detail",
+			err:        fmt.Errorf("%w: cannot move content from draft to published", service.ErrInvalidTransition),
+			wantStatus: http.StatusConflict,
+			wantBody: map[string]interface{}{
+				"status": "fail",
+				"data": map[string]interface{}{"code": "invalid_transition",
+					"message": "invalid status transition: cannot move content from draft to published"},
+			},
+		},
+		{
+			name:       "precondition failed",
+			err:        service.ErrPreconditionFailed,
+			wantStatus: http.StatusPreconditionFailed,
+			wantBody: map[string]interface{}{
+				"status": "fail",
+				"data":   map[string]interface{}{"code": "precondition_failed", "message": "content has been modified"},
+			},
+		},
+		{
+			name: "validation with fields",
+			err: service.ErrValidation.WithFields([]apperror.FieldError{
+				{Field: "details[0].value", Message: "must be at most 5 characters"},
+				{Field: "details[0].value", Message: "ignored"},
+			}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: map[string]interface{}{
+				"status": "fail",
+				"data": map[string]interface{}{"code": "validation_failed", "message": "validation failed",
+					"fields": map[string]interface{}{"details[0].value": "must be at most 5 characters"}},
+			},
+		},
+		{
+			name:       "unexpected error",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   map[string]interface{}{"status": "error", "message": "connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			writeError(rec, httptest.NewRequest(http.MethodGet, "/content/1", nil), tt.err, "test")
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("writeError() status = %d, expected = %d", rec.Code, tt.wantStatus)
+			}
+			var body map[string]interface{}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("writeError() body = %v, expected = %v", body, tt.wantBody)
+			}
+		})
+	}
+}
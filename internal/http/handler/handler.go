@@ -2,10 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/http/middleware"
 	"github.com/g-stro/content-management-service/internal/http/response"
+	"github.com/g-stro/content-management-service/internal/model"
 	"github.com/g-stro/content-management-service/internal/service"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type Handler struct {
@@ -18,6 +25,18 @@ func NewContentHandler(svc *service.Service) *Handler {
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/content", h.handleContentRequests)
+	mux.HandleFunc("/content/{id}", h.handleContentItemRequests)
+	mux.HandleFunc("/content/trash", h.handleTrashRequests)
+	mux.HandleFunc("/content/search", h.handleSearchRequests)
+	mux.HandleFunc("/content/{id}/restore", h.handleRestoreRequests)
+	mux.HandleFunc("/content/{id}/status", h.handleStatusRequests)
+	mux.HandleFunc("/content/{id}/details/order", h.handleDetailOrderRequests)
+	mux.HandleFunc("/content/{id}/revisions", h.handleRevisionsRequests)
+	mux.HandleFunc("/content/{id}/revisions/diff", h.handleRevisionDiffRequests)
+	mux.HandleFunc("/content/{id}/revisions/{rev}", h.handleRevisionRequests)
+	mux.HandleFunc("/content/{id}/revisions/{rev}/restore", h.handleRevisionRestoreRequests)
+	mux.HandleFunc("/content-types", h.handleContentTypesRequests)
+	mux.HandleFunc("/content-types/{id}", h.handleContentTypeRequests)
 }
 
 func (h *Handler) handleContentRequests(w http.ResponseWriter, r *http.Request) {
@@ -31,61 +50,138 @@ func (h *Handler) handleContentRequests(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+func (h *Handler) handleContentItemRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getContentByID(w, r)
+	case http.MethodPut:
+		h.updateContent(w, r)
+	case http.MethodPatch:
+		h.patchContent(w, r)
+	case http.MethodDelete:
+		h.deleteContent(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleTrashRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getDeletedContent(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleSearchRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.searchContent(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleStatusRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.transitionContent(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleDetailOrderRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.reorderDetails(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleRestoreRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.restoreContent(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
 func (h *Handler) getContent(w http.ResponseWriter, r *http.Request) {
-	content, err := h.svc.GetContent()
+	query, err := parseContentQuery(r)
+	if err != nil {
+		writeError(w, r, err, "invalid content query")
+		return
+	}
+
+	// Public callers only ever see published content
+	if query.Status != "" && query.Status != string(model.StatusPublished) && !requireEditor(w, r) {
+		return
+	}
+
+	page, err := h.svc.GetContent(r.Context(), query)
 	if err != nil {
-		response.HttpError(w, err, http.StatusInternalServerError, "failed to retrieve content")
+		writeError(w, r, err, "failed to retrieve content")
 		return
 	}
 
-	if len(content) == 0 {
-		response.HttpSuccess(w, map[string]interface{}{
+	if len(page.Content) == 0 {
+		response.HttpSuccess(w, r, map[string]interface{}{
 			"content": []response.GetContent{},
 		}, http.StatusOK, "No content available")
 		return
 	}
 
 	contentResp := make([]response.GetContent, 0)
-	for _, c := range content {
-		details := make([]response.Details, 0)
-		for _, d := range c.Details {
-			dr := response.Details{
-				ContentType: d.ContentType,
-				Value:       d.Value,
-			}
-			details = append(details, dr)
-		}
+	for _, c := range page.Content {
+		contentResp = append(contentResp, toGetContentResponse(c))
+	}
 
-		cr := response.GetContent{
-			ID:          c.ID,
-			Name:        c.Name,
-			Description: c.Description,
-			Details:     details,
-		}
-		contentResp = append(contentResp, cr)
+	resp := response.ListContent{
+		Content:    contentResp,
+		NextCursor: page.NextCursor,
 	}
 
-	resp := struct {
-		Content []response.GetContent `json:"content"`
-	}{
-		Content: contentResp,
+	response.HttpSuccess(w, r, resp, http.StatusOK, "content retrieved successfully")
+}
+
+func (h *Handler) getContentByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
 	}
 
-	response.HttpSuccess(w, resp, http.StatusOK, "content retrieved successfully")
+	content, err := h.svc.GetContentByID(r.Context(), id)
+	if err == nil && content.Status != string(model.StatusPublished) && !middleware.IsEditor(r.Context()) {
+		err = service.ErrContentNotFound // Unpublished content is hidden from public callers
+	}
+	if err != nil {
+		writeError(w, r, err, "failed to retrieve content")
+		return
+	}
+
+	w.Header().Set("ETag", service.ContentETag(content))
+	response.HttpSuccess(w, r, toGetContentResponse(content), http.StatusOK, "content retrieved successfully")
 }
 
 func (h *Handler) createContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
 	var req dto.Content
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		response.HttpFail(
-			w, "invalid request body", http.StatusBadRequest, "invalid request body")
+		writeError(w, r, errInvalidBody, "invalid request body")
 		return
 	}
 
-	content, err := h.svc.CreateContent(req)
+	content, err := h.svc.CreateContent(r.Context(), req, requestAuthor(r))
 	if err != nil {
-		response.HttpError(w, err, http.StatusInternalServerError, "failed to create content")
+		writeError(w, r, err, "failed to create content")
 		return
 	}
 
@@ -97,5 +193,343 @@ func (h *Handler) createContent(w http.ResponseWriter, r *http.Request) {
 		CreationDate: formattedCreationDate,
 	}
 
-	response.HttpSuccess(w, resp, http.StatusCreated, "content created successfully")
+	response.HttpSuccess(w, r, resp, http.StatusCreated, "content created successfully")
+}
+
+// toGetContentResponse converts a content DTO into its API response representation
+func toGetContentResponse(c *dto.Content) response.GetContent {
+	details := make([]response.Details, 0)
+	for _, d := range c.Details {
+		dr := response.Details{
+			ContentType: d.ContentType,
+			Value:       d.Value,
+		}
+		details = append(details, dr)
+	}
+
+	return response.GetContent{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Status:      c.Status,
+		PublishAt:   formatOptionalTime(c.PublishAt),
+		UnpublishAt: formatOptionalTime(c.UnpublishAt),
+		DeletedAt:   formatOptionalTime(c.DeletedAt),
+		Details:     details,
+	}
+}
+
+func (h *Handler) updateContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.Content
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, r, errInvalidBody, "invalid request body")
+		return
+	}
+
+	content, err := h.svc.UpdateContent(r.Context(), id, req, requestAuthor(r), r.Header.Get("If-Match"))
+	if err != nil {
+		writeError(w, r, err, "failed to update content")
+		return
+	}
+
+	w.Header().Set("ETag", service.ContentETag(content))
+	response.HttpSuccess(w, r, toUpdateContentResponse(content), http.StatusOK, "content updated successfully")
+}
+
+func (h *Handler) patchContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "" && mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		writeError(w, r, errUnsupportedPatchType, "unsupported patch content type")
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil || len(patch) == 0 {
+		writeError(w, r, errInvalidBody, "invalid request body")
+		return
+	}
+
+	content, err := h.svc.PatchContent(r.Context(), id, patch, requestAuthor(r), r.Header.Get("If-Match"))
+	if err != nil {
+		writeError(w, r, err, "failed to patch content")
+		return
+	}
+
+	w.Header().Set("ETag", service.ContentETag(content))
+	response.HttpSuccess(w, r, toUpdateContentResponse(content), http.StatusOK, "content patched successfully")
+}
+
+func (h *Handler) searchContent(w http.ResponseWriter, r *http.Request) {
+	var limit int
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			writeError(w, r, fmt.Errorf("%w: limit must be a positive integer", errInvalidQuery), "invalid search query")
+			return
+		}
+	}
+
+	results, err := h.svc.SearchContent(r.Context(), r.URL.Query().Get("q"), limit, middleware.IsEditor(r.Context()))
+	if err != nil {
+		writeError(w, r, err, "failed to search content")
+		return
+	}
+
+	resultsResp := make([]response.SearchResult, 0)
+	for _, res := range results {
+		resultsResp = append(resultsResp, response.SearchResult{
+			GetContent: toGetContentResponse(res.Content),
+			Score:      res.Score,
+			Snippet:    res.Snippet,
+		})
+	}
+
+	resp := struct {
+		Results []response.SearchResult `json:"results"`
+	}{
+		Results: resultsResp,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "content searched successfully")
+}
+
+func (h *Handler) deleteContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	// Permanent deletion purges the content and its details instead of moving it to the trash
+	permanent, err := strconv.ParseBool(r.URL.Query().Get("permanent"))
+	if err != nil && r.URL.Query().Has("permanent") {
+		writeError(w, r, fmt.Errorf("%w: invalid permanent flag", errInvalidQuery), "invalid permanent flag")
+		return
+	}
+
+	if permanent {
+		err = h.svc.PurgeContent(r.Context(), id, r.Header.Get("If-Match"))
+	} else {
+		err = h.svc.DeleteContent(r.Context(), id, r.Header.Get("If-Match"))
+	}
+	if err != nil {
+		writeError(w, r, err, "failed to delete content")
+		return
+	}
+
+	resp := response.DeleteContent{
+		ID:        id,
+		Permanent: permanent,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "content deleted successfully")
+}
+
+func (h *Handler) getDeletedContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	content, err := h.svc.GetDeletedContent(r.Context())
+	if err != nil {
+		writeError(w, r, err, "failed to retrieve deleted content")
+		return
+	}
+
+	contentResp := make([]response.GetContent, 0)
+	for _, c := range content {
+		contentResp = append(contentResp, toGetContentResponse(c))
+	}
+
+	resp := struct {
+		Content []response.GetContent `json:"content"`
+	}{
+		Content: contentResp,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "deleted content retrieved successfully")
+}
+
+func (h *Handler) restoreContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	content, err := h.svc.RestoreContent(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "failed to restore content")
+		return
+	}
+
+	response.HttpSuccess(w, r, toGetContentResponse(content), http.StatusOK, "content restored successfully")
+}
+
+// parseContentQuery reads the pagination, sorting and filtering query parameters of a content listing
+func parseContentQuery(r *http.Request) (dto.ContentQuery, error) {
+	params := r.URL.Query()
+	query := dto.ContentQuery{
+		Cursor:      params.Get("cursor"),
+		SortBy:      params.Get("sort"),
+		Status:      params.Get("status"),
+		NamePrefix:  params.Get("name_prefix"),
+		ContentType: params.Get("content_type"),
+	}
+
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return query, fmt.Errorf("%w: limit must be a positive integer", errInvalidQuery)
+		}
+		query.Limit = limit
+	}
+
+	switch params.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return query, fmt.Errorf("%w: order must be either asc or desc", errInvalidQuery)
+	}
+
+	for name, target := range map[string]**time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+	} {
+		if v := params.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return query, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", errInvalidQuery, name)
+			}
+			*target = &t
+		}
+	}
+
+	return query, nil
+}
+
+func (h *Handler) transitionContent(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, r, errInvalidBody, "invalid request body")
+		return
+	}
+
+	content, err := h.svc.TransitionContent(r.Context(), id, req.Status, requestAuthor(r), r.Header.Get("If-Match"))
+	if err != nil {
+		writeError(w, r, err, "failed to transition content")
+		return
+	}
+
+	w.Header().Set("ETag", service.ContentETag(content))
+	response.HttpSuccess(w, r, toGetContentResponse(content), http.StatusOK, "content status updated successfully")
+}
+
+func (h *Handler) reorderDetails(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Order []int `json:"order"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, r, errInvalidBody, "invalid request body")
+		return
+	}
+
+	content, err := h.svc.ReorderDetails(r.Context(), id, req.Order, requestAuthor(r), r.Header.Get("If-Match"))
+	if err != nil {
+		writeError(w, r, err, "failed to reorder content details")
+		return
+	}
+
+	w.Header().Set("ETag", service.ContentETag(content))
+	response.HttpSuccess(w, r, toGetContentResponse(content), http.StatusOK, "content details reordered successfully")
+}
+
+// requireEditor writes a fail response and returns false unless the request was made by an editor
+func requireEditor(w http.ResponseWriter, r *http.Request) bool {
+	if !middleware.IsEditor(r.Context()) {
+		writeError(w, r, errEditorRequired, "editor access required")
+		return false
+	}
+	return true
+}
+
+// requestAuthor returns the authenticated editor making the request, used to attribute content revisions
+func requestAuthor(r *http.Request) string {
+	return middleware.EditorName(r.Context())
+}
+
+// parseContentID extracts the content ID path value, writing a fail response if it is invalid
+func parseContentID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, r, errInvalidContentID, "invalid content ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// formatOptionalTime formats a timestamp for a response, or returns an empty string if it is not set
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// toUpdateContentResponse converts an updated content DTO into its API response representation
+func toUpdateContentResponse(c *dto.Content) response.UpdateContent {
+	return response.UpdateContent{
+		ID:               c.ID,
+		Name:             c.Name,
+		LastModifiedDate: c.LastModifiedDate.Format("2006-01-02 15:04:05"),
+	}
 }
@@ -0,0 +1,265 @@
+//go:build !integration
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/http/middleware"
+	"github.com/g-stro/content-management-service/internal/http/response"
+	"github.com/g-stro/content-management-service/internal/repository"
+	"github.com/g-stro/content-management-service/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testEditorName   = "alice"
+	testEditorAPIKey = "editor-key"
+)
+
+func TestContentWritesRequireEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "create", method: http.MethodPost, path: "/content",
+			body: `{"name":"New","details":[{"content_type":"text","value":"Hello"}]}`},
+		{name: "update", method: http.MethodPut, path: "/content/1",
+			body: `{"name":"Changed","details":[{"content_type":"text","value":"Hello"}]}`},
+		{name: "patch", method: http.MethodPatch, path: "/content/1", body: `{"name":"Changed"}`},
+		{name: "delete", method: http.MethodDelete, path: "/content/1"},
+		{name: "permanent delete", method: http.MethodDelete, path: "/content/1?permanent=true"},
+		{name: "restore", method: http.MethodPost, path: "/content/1/restore"},
+		{name: "transition", method: http.MethodPost, path: "/content/1/status", body: `{"status":"review"}`},
+		{name: "reorder details", method: http.MethodPut, path: "/content/1/details/order", body: `{"order":[1]}`},
+	}
+
+	for _, tt := range tests {
+		for _, auth := range []string{"", "Bearer wrong-key"} {
+			t.Run(tt.name+" "+auth, func(t *testing.T) {
+				repo := repository.NewInMemoryContentRepository()
+				svc := service.NewContentService(repo, nil, nil)
+				existing, err := svc.CreateContent(context.Background(), dto.Content{
+					Name:    "Existing",
+					Details: []dto.Details{{ContentType: "text", Value: "Hello"}},
+				}, "")
+				if err != nil {
+					t.Fatalf("CreateContent() error = %v", err)
+				}
+				mux := http.NewServeMux()
+				NewContentHandler(svc).RegisterRoutes(mux)
+				server := middleware.EditorMiddleware(map[string]string{testEditorName: testEditorAPIKey})(mux)
+
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set("If-Match", `"1-0"`) // A stale version must not reveal that the content exists
+				if auth != "" {
+					req.Header.Set("Authorization", auth)
+				}
+				rec := httptest.NewRecorder()
+				server.ServeHTTP(rec, req)
+
+				if rec.Code != http.StatusForbidden {
+					t.Fatalf("%s %s status got = %d, expected = %d", tt.method, tt.path, rec.Code,
+						http.StatusForbidden)
+				}
+				if !strings.Contains(rec.Body.String(), "editor_required") {
+					t.Errorf("%s %s body got = %s, expected code editor_required", tt.method, tt.path,
+						rec.Body.String())
+				}
+
+				current, err := svc.GetContentByID(context.Background(), existing.ID)
+				if err != nil {
+					t.Fatalf("GetContentByID() error = %v", err)
+				}
+				if current.Name != existing.Name || current.Status != existing.Status ||
+					!current.LastModifiedDate.Equal(existing.LastModifiedDate) {
+					t.Errorf("%s %s modified content, got = %+v", tt.method, tt.path, current)
+				}
+			})
+		}
+	}
+}
+
+func TestContentWritesAllowEditor(t *testing.T) {
+	repo := repository.NewInMemoryContentRepository()
+	svc := service.NewContentService(repo, nil, nil)
+	mux := http.NewServeMux()
+	NewContentHandler(svc).RegisterRoutes(mux)
+	server := middleware.EditorMiddleware(map[string]string{testEditorName: testEditorAPIKey})(mux)
+
+	steps := []struct {
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{method: http.MethodPost, path: "/content",
+			body: `{"name":"New","details":[{"content_type":"text","value":"Hello"}]}`, expectedCode: http.StatusCreated},
+		{method: http.MethodPatch, path: "/content/1", body: `{"name":"Changed"}`, expectedCode: http.StatusOK},
+		{method: http.MethodPost, path: "/content/1/status", body: `{"status":"review"}`, expectedCode: http.StatusOK},
+		{method: http.MethodDelete, path: "/content/1", expectedCode: http.StatusOK},
+		{method: http.MethodPost, path: "/content/1/restore", expectedCode: http.StatusOK},
+		{method: http.MethodDelete, path: "/content/1?permanent=true", expectedCode: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		req.Header.Set("Authorization", "Bearer "+testEditorAPIKey)
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != step.expectedCode {
+			t.Fatalf("%s %s status got = %d, expected = %d, body = %s", step.method, step.path, rec.Code,
+				step.expectedCode, rec.Body.String())
+		}
+	}
+}
+
+func TestConditionalContentWrites(t *testing.T) {
+	repo := repository.NewInMemoryContentRepository()
+	svc := service.NewContentService(repo, nil, nil)
+	mux := http.NewServeMux()
+	NewContentHandler(svc).RegisterRoutes(mux)
+	server := middleware.EditorMiddleware(map[string]string{testEditorName: testEditorAPIKey})(mux)
+
+	serve := func(method, path, body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+testEditorAPIKey)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	serve(http.MethodPost, "/content", `{"name":"New","details":[{"content_type":"text","value":"Hello"}]}`, "")
+	original := serve(http.MethodGet, "/content/1", "", "").Header().Get("ETag")
+	if original == "" {
+		t.Fatalf("GET /content/1 returned no ETag")
+	}
+
+	patched := serve(http.MethodPatch, "/content/1", `{"name":"Changed"}`, original)
+	if patched.Code != http.StatusOK || patched.Header().Get("ETag") == original {
+		t.Fatalf("PATCH with current ETag got = %d, ETag %q, expected = %d and a new ETag", patched.Code,
+			patched.Header().Get("ETag"), http.StatusOK)
+	}
+
+	stale := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodPut, path: "/content/1",
+			body: `{"name":"Other","details":[{"content_type":"text","value":"Hello"}]}`},
+		{method: http.MethodPatch, path: "/content/1", body: `{"name":"Other"}`},
+		{method: http.MethodPost, path: "/content/1/status", body: `{"status":"review"}`},
+		{method: http.MethodPut, path: "/content/1/details/order", body: `{"order":[0]}`},
+		{method: http.MethodDelete, path: "/content/1"},
+		{method: http.MethodDelete, path: "/content/1?permanent=true"},
+	}
+	for _, tt := range stale {
+		if rec := serve(tt.method, tt.path, tt.body, original); rec.Code != http.StatusPreconditionFailed {
+			t.Errorf("%s %s with stale ETag status got = %d, expected = %d", tt.method, tt.path, rec.Code,
+				http.StatusPreconditionFailed)
+		}
+	}
+
+	if rec := serve(http.MethodDelete, "/content/2", "", original); rec.Code != http.StatusNotFound {
+		t.Errorf("DELETE /content/2 with ETag status got = %d, expected = %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := serve(http.MethodDelete, "/content/1", "", patched.Header().Get("ETag")); rec.Code != http.StatusOK {
+		t.Errorf("DELETE /content/1 with current ETag status got = %d, expected = %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestContentRevisionsAttributeTheEditor(t *testing.T) {
+	repo := repository.NewInMemoryContentRepository()
+	svc := service.NewContentService(repo, nil, nil)
+	mux := http.NewServeMux()
+	NewContentHandler(svc).RegisterRoutes(mux)
+	server := middleware.EditorMiddleware(map[string]string{testEditorName: testEditorAPIKey})(mux)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+testEditorAPIKey)
+		req.Header.Set("X-User", "mallory") // Unauthenticated, so it must not be used as the author
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	serve(http.MethodPost, "/content", `{"name":"New","details":[{"content_type":"text","value":"Hello"}]}`)
+	serve(http.MethodPatch, "/content/1", `{"name":"Changed"}`)
+
+	revisions, err := svc.GetRevisions(context.Background(), 1)
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("GetRevisions() got = %d revisions, %v, expected = 2", len(revisions), err)
+	}
+	for _, revision := range revisions {
+		if revision.Author != testEditorName {
+			t.Errorf("revision %d author got = %q, expected = %q", revision.Revision, revision.Author, testEditorName)
+		}
+	}
+}
+
+func TestContentCursorRequiresTheSameSort(t *testing.T) {
+	repo := repository.NewInMemoryContentRepository()
+	svc := service.NewContentService(repo, nil, nil)
+	for _, name := range []string{"Alpha", "Bravo"} {
+		if _, err := svc.CreateContent(context.Background(), dto.Content{
+			Name:    name,
+			Details: []dto.Details{{ContentType: "text", Value: "Hello"}},
+		}, ""); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+	}
+	mux := http.NewServeMux()
+	NewContentHandler(svc).RegisterRoutes(mux)
+	server := middleware.EditorMiddleware(map[string]string{testEditorName: testEditorAPIKey})(mux)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+testEditorAPIKey) // Drafts are only listed for editors
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := serve("/content?status=any&sort=last_modified_date&limit=1")
+	var body struct {
+		Data response.ListContent `json:"data"`
+	}
+	if err := json.NewDecoder(first.Body).Decode(&body); err != nil || body.Data.NextCursor == "" {
+		t.Fatalf("GET /content first page got = %+v, %v, expected a next cursor", body.Data, err)
+	}
+	cursor := body.Data.NextCursor
+
+	forged := repository.EncodeCursor(repository.Cursor{SortBy: repository.SortByCreationDate, Value: "alpha", ID: 1})
+	paths := []string{
+		"/content?status=any&sort=name&limit=1&cursor=" + cursor,
+		"/content?status=any&sort=last_modified_date&order=desc&limit=1&cursor=" + cursor,
+		"/content?status=any&sort=creation_date&limit=1&cursor=" + forged,
+	}
+	for _, path := range paths {
+		rec := serve(path)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status got = %d, expected = %d, body = %s", path, rec.Code, http.StatusBadRequest,
+				rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), "invalid_query") {
+			t.Errorf("GET %s body got = %s, expected code invalid_query", path, rec.Body.String())
+		}
+	}
+
+	next := serve("/content?status=any&sort=last_modified_date&limit=1&cursor=" + cursor)
+	if next.Code != http.StatusOK {
+		t.Errorf("GET /content next page status got = %d, expected = %d", next.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/g-stro/content-management-service/internal/health"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"net/http"
+)
+
+// HealthHandler serves the liveness and readiness probes. Probes are polled frequently by orchestrators, so their
+// responses are plain JSON and successful probes are not logged.
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+func (h *HealthHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", h.handleLiveness)
+	mux.HandleFunc("/readyz", h.handleReadiness)
+}
+
+// handleLiveness reports that the process is up and serving requests, without checking any dependency
+func (h *HealthHandler) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeHealth(r.Context(), w, http.StatusOK, map[string]string{"status": health.StatusOK})
+}
+
+// handleReadiness reports whether every dependency is usable, with the result of each check
+func (h *HealthHandler) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := h.checker.Run(r.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		logging.FromContext(r.Context()).Warn("service is not ready", "checks", report.Checks)
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(r.Context(), w, status, report)
+}
+
+func writeHealth(ctx context.Context, w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logging.FromContext(ctx).Error("failed to encode health response into JSON", "error", err)
+	}
+}
@@ -0,0 +1,65 @@
+//go:build !integration
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/health"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		method         string
+		databaseErr    error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{name: "liveness", path: "/healthz", method: http.MethodGet, expectedCode: http.StatusOK,
+			expectedStatus: health.StatusOK},
+		{name: "liveness ignores dependencies", path: "/healthz", method: http.MethodGet,
+			databaseErr: errors.New("connection refused"), expectedCode: http.StatusOK, expectedStatus: health.StatusOK},
+		{name: "ready", path: "/readyz", method: http.MethodGet, expectedCode: http.StatusOK,
+			expectedStatus: health.StatusOK},
+		{name: "not ready", path: "/readyz", method: http.MethodGet, databaseErr: errors.New("connection refused"),
+			expectedCode: http.StatusServiceUnavailable, expectedStatus: health.StatusUnavailable},
+		{name: "invalid method", path: "/readyz", method: http.MethodPost, expectedCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.NewChecker(health.DefaultTimeout)
+			checker.Add("database", func(context.Context) error { return tt.databaseErr })
+			mux := http.NewServeMux()
+			NewHealthHandler(checker).RegisterRoutes(mux)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("%s %s status got = %d, expected = %d", tt.method, tt.path, rec.Code, tt.expectedCode)
+			}
+			if tt.expectedStatus == "" {
+				return
+			}
+
+			var body health.Report
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Status != tt.expectedStatus {
+				t.Errorf("%s %s body status got = %s, expected = %s", tt.method, tt.path, body.Status,
+					tt.expectedStatus)
+			}
+			if tt.path == "/readyz" && body.Checks["database"].Status != tt.expectedStatus {
+				t.Errorf("%s %s database check got = %+v", tt.method, tt.path, body.Checks["database"])
+			}
+		})
+	}
+}
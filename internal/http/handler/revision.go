@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/http/response"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) handleRevisionsRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getRevisions(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleRevisionRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getRevision(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleRevisionDiffRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.diffRevisions(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleRevisionRestoreRequests(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.restoreRevision(w, r)
+	default:
+		http.Error(w, "invalid request method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) getRevisions(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.svc.GetRevisions(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err, "failed to retrieve revisions")
+		return
+	}
+
+	revisionsResp := make([]response.Revision, 0)
+	for _, rev := range revisions {
+		// The listing only carries revision metadata, snapshots are fetched individually
+		revisionsResp = append(revisionsResp, toRevisionResponse(rev, false))
+	}
+
+	resp := struct {
+		Revisions []response.Revision `json:"revisions"`
+	}{
+		Revisions: revisionsResp,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "revisions retrieved successfully")
+}
+
+func (h *Handler) getRevision(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(w, r, r.PathValue("rev"), "rev")
+	if !ok {
+		return
+	}
+
+	revision, err := h.svc.GetRevision(r.Context(), id, rev)
+	if err != nil {
+		writeError(w, r, err, "failed to retrieve revision")
+		return
+	}
+
+	response.HttpSuccess(w, r, toRevisionResponse(revision, true), http.StatusOK, "revision retrieved successfully")
+}
+
+func (h *Handler) diffRevisions(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+	from, ok := parseRevision(w, r, r.URL.Query().Get("from"), "from")
+	if !ok {
+		return
+	}
+	to, ok := parseRevision(w, r, r.URL.Query().Get("to"), "to")
+	if !ok {
+		return
+	}
+
+	changes, err := h.svc.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		writeError(w, r, err, "failed to diff revisions")
+		return
+	}
+
+	changesResp := make([]response.FieldChange, 0)
+	for _, c := range changes {
+		changesResp = append(changesResp, response.FieldChange{
+			Field: c.Field,
+			From:  c.From,
+			To:    c.To,
+		})
+	}
+
+	resp := struct {
+		From    int                    `json:"from"`
+		To      int                    `json:"to"`
+		Changes []response.FieldChange `json:"changes"`
+	}{
+		From:    from,
+		To:      to,
+		Changes: changesResp,
+	}
+
+	response.HttpSuccess(w, r, resp, http.StatusOK, "revisions compared successfully")
+}
+
+func (h *Handler) restoreRevision(w http.ResponseWriter, r *http.Request) {
+	if !requireEditor(w, r) {
+		return
+	}
+
+	id, ok := parseContentID(w, r)
+	if !ok {
+		return
+	}
+	rev, ok := parseRevision(w, r, r.PathValue("rev"), "rev")
+	if !ok {
+		return
+	}
+
+	content, err := h.svc.RestoreRevision(r.Context(), id, rev, requestAuthor(r))
+	if err != nil {
+		writeError(w, r, err, "failed to restore revision")
+		return
+	}
+
+	response.HttpSuccess(w, r, toUpdateContentResponse(content), http.StatusOK, "revision restored successfully")
+}
+
+// parseRevision parses a revision number, writing a fail response if it is invalid
+func parseRevision(w http.ResponseWriter, r *http.Request, value, name string) (int, bool) {
+	rev, err := strconv.Atoi(value)
+	if err != nil || rev <= 0 {
+		writeError(w, r, fmt.Errorf("%w %s", errInvalidRevision, name), "invalid revision")
+		return 0, false
+	}
+	return rev, true
+}
+
+// toRevisionResponse converts a revision DTO into its API response representation
+func toRevisionResponse(r *dto.Revision, withContent bool) response.Revision {
+	resp := response.Revision{
+		Revision:  r.Revision,
+		Author:    r.Author,
+		CreatedAt: r.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if withContent {
+		content := toGetContentResponse(r.Content)
+		resp.Content = &content
+	}
+	return resp
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultEditorName names the editor authenticated with the single key of EDITOR_API_KEY
+const DefaultEditorName = "editor"
+
+type editorKey struct{}
+
+// EditorMiddleware marks requests authenticated with the API key of an editor, given by editor name, as requests of
+// that editor. Requests without a valid key are treated as public. No API keys disable editor access.
+func EditorMiddleware(apiKeys map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if found {
+				if name := matchEditor(apiKeys, token); name != "" {
+					r = r.WithContext(context.WithValue(r.Context(), editorKey{}, name))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchEditor returns the name of the editor whose API key is the token, or an empty string if there is none. Every
+// key is compared in constant time, so the time taken reveals neither the key nor which editor it belongs to.
+func matchEditor(apiKeys map[string]string, token string) string {
+	var match string
+	for name, key := range apiKeys {
+		if key != "" && subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+			match = name
+		}
+	}
+	return match
+}
+
+// IsEditor reports whether the request context belongs to an authenticated editor
+func IsEditor(ctx context.Context) bool {
+	return EditorName(ctx) != ""
+}
+
+// EditorName returns the name of the authenticated editor of the request context, or an empty string for public
+// callers
+func EditorName(ctx context.Context) string {
+	name, _ := ctx.Value(editorKey{}).(string)
+	return name
+}
+
+// ParseEditorAPIKeys parses a comma-separated list of name:key pairs into API keys by editor name. Names and keys
+// must be unique and not empty.
+func ParseEditorAPIKeys(s string) (map[string]string, error) {
+	apiKeys := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return apiKeys, nil
+	}
+
+	keys := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		name, key, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || name == "" || key == "" {
+			return nil, errors.New("editor API keys must be comma-separated name:key pairs")
+		}
+		if _, ok := apiKeys[name]; ok {
+			return nil, fmt.Errorf("duplicate editor name %q", name)
+		}
+		if keys[key] {
+			return nil, fmt.Errorf("editor %q reuses the API key of another editor", name)
+		}
+		apiKeys[name] = key
+		keys[key] = true
+	}
+	return apiKeys, nil
+}
@@ -0,0 +1,77 @@
+//go:build !integration
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestEditorMiddleware(t *testing.T) {
+	apiKeys := map[string]string{"alice": "alice-key", "bob": "bob-key"}
+	tests := []struct {
+		name          string
+		authorization string
+		expected      string
+	}{
+		{name: "first editor", authorization: "Bearer alice-key", expected: "alice"},
+		{name: "second editor", authorization: "Bearer bob-key", expected: "bob"},
+		{name: "wrong key", authorization: "Bearer carol-key"},
+		{name: "not a bearer token", authorization: "alice-key"},
+		{name: "empty token", authorization: "Bearer "},
+		{name: "no authorization"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var name string
+			var editor bool
+			handler := EditorMiddleware(apiKeys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				name, editor = EditorName(r.Context()), IsEditor(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/content", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if name != tt.expected || editor != (tt.expected != "") {
+				t.Errorf("EditorName() got = %q, IsEditor() got = %v, expected = %q", name, editor, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseEditorAPIKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{name: "empty", value: "", expected: map[string]string{}},
+		{name: "single", value: "alice:alice-key", expected: map[string]string{"alice": "alice-key"}},
+		{name: "several with spaces", value: " alice:alice-key , bob:bob:key ",
+			expected: map[string]string{"alice": "alice-key", "bob": "bob:key"}},
+		{name: "missing key", value: "alice", expectErr: true},
+		{name: "empty name", value: ":alice-key", expectErr: true},
+		{name: "empty key", value: "alice:", expectErr: true},
+		{name: "duplicate name", value: "alice:one,alice:two", expectErr: true},
+		{name: "duplicate key", value: "alice:key,bob:key", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEditorAPIKeys(tt.value)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseEditorAPIKeys() error = %v, expectErr = %v", err, tt.expectErr)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseEditorAPIKeys() got = %v, expected = %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RequestIDHeader carries the ID correlating the logs of a request, within this service and across services
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs accepted from clients, which end up in every log line of the request
+const maxRequestIDLength = 128
+
+// AccessLogMiddleware assigns every request an ID, reusing a valid X-Request-ID header of the client and returning it
+// in the response. It puts a logger with the request ID, and the trace ID of a traced request, into the request
+// context, and writes one access log line per request once it has been served, including the editor authenticated by
+// an outer EditorMiddleware and, at debug level, the request headers. Successful requests to quietPaths, such as
+// health probes, are not logged.
+func AccessLogMiddleware(logger *slog.Logger, quietPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			reqLogger := logger.With("request_id", requestID)
+			if span := tracing.SpanFromContext(r.Context()); span != nil {
+				reqLogger = reqLogger.With("trace_id", span.SpanContext().TraceID.String())
+			}
+
+			recorder := newStatusRecorder(w)
+			next.ServeHTTP(recorder, r.WithContext(logging.WithLogger(r.Context(), reqLogger)))
+
+			if recorder.status < http.StatusBadRequest && slices.Contains(quietPaths, r.URL.Path) {
+				return
+			}
+			level := slog.LevelInfo
+			if recorder.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.status),
+				slog.Int64("bytes", recorder.bytes),
+				slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("client_ip", clientIP(r)),
+				slog.String("editor", EditorName(r.Context())),
+			}
+			if reqLogger.Enabled(r.Context(), slog.LevelDebug) {
+				// Sensitive headers such as Authorization are redacted by the logger
+				attrs = append(attrs, slog.Any("headers", r.Header))
+			}
+			reqLogger.LogAttrs(r.Context(), level, "request served", attrs...)
+		})
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to log: non-empty, bounded and printable ASCII
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // Never fails
+	return hex.EncodeToString(b)
+}
+
+// clientIP returns the address of the connection the request came from. Forwarding headers are ignored, as any
+// client can set them.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,91 @@
+//go:build !integration
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	tests := []struct {
+		name              string
+		path              string
+		requestID         string
+		status            int
+		expectedRequestID string // Empty if a new ID must be generated
+		expectedLines     int
+	}{
+		{name: "propagated request ID", path: "/content", requestID: "abc-123", status: http.StatusOK,
+			expectedRequestID: "abc-123", expectedLines: 2},
+		{name: "generated request ID", path: "/content", status: http.StatusOK, expectedLines: 2},
+		{name: "invalid request ID", path: "/content", requestID: "abc\n123", status: http.StatusOK, expectedLines: 2},
+		{name: "too long request ID", path: "/content", requestID: strings.Repeat("a", maxRequestIDLength+1),
+			status: http.StatusOK, expectedLines: 2},
+		{name: "quiet path", path: "/healthz", status: http.StatusOK, expectedLines: 1},
+		{name: "failing quiet path", path: "/healthz", status: http.StatusServiceUnavailable, expectedLines: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				logging.FromContext(r.Context()).Info("handled")
+				w.WriteHeader(tt.status)
+				w.Write([]byte("hello"))
+			})
+			handler := EditorMiddleware(map[string]string{"alice": "alice-key"})(AccessLogMiddleware(logger, "/healthz")(next))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer alice-key")
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(RequestIDHeader)
+			if tt.expectedRequestID != "" && requestID != tt.expectedRequestID {
+				t.Errorf("%s got = %s, expected = %s", RequestIDHeader, requestID, tt.expectedRequestID)
+			}
+			if tt.expectedRequestID == "" && (len(requestID) != 32 || requestID == tt.requestID) {
+				t.Errorf("%s got = %q, expected a generated ID", RequestIDHeader, requestID)
+			}
+
+			var lines []map[string]any
+			decoder := json.NewDecoder(&buf)
+			for decoder.More() {
+				var line map[string]any
+				if err := decoder.Decode(&line); err != nil {
+					t.Fatalf("failed to decode log line: %v", err)
+				}
+				if line["request_id"] != requestID {
+					t.Errorf("log line request_id got = %v, expected = %s", line["request_id"], requestID)
+				}
+				lines = append(lines, line)
+			}
+			if len(lines) != tt.expectedLines {
+				t.Fatalf("log lines got = %d, expected = %d", len(lines), tt.expectedLines)
+			}
+			if tt.expectedLines == 1 {
+				return
+			}
+
+			access := lines[1]
+			expected := map[string]any{"msg": "request served", "method": "GET", "path": tt.path,
+				"status": float64(tt.status), "bytes": float64(5), "client_ip": "192.0.2.1", "editor": "alice"}
+			for key, value := range expected {
+				if access[key] != value {
+					t.Errorf("access log %s got = %v, expected = %v", key, access[key], value)
+				}
+			}
+		})
+	}
+}
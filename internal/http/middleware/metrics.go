@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests that match no registered route, so that arbitrary paths do not create new series
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a non-standard method, so that arbitrary methods do not create new series
+const otherMethod = "other"
+
+// MetricsMiddleware counts requests and observes their duration, labelled by the pattern of the route in routes that
+// matches the request, the method and the response status
+func MetricsMiddleware(reg *metrics.Registry, routes *http.ServeMux) func(http.Handler) http.Handler {
+	requests := reg.NewCounter("cms_http_requests_total", "Total number of HTTP requests.",
+		"route", "method", "status")
+	duration := reg.NewHistogram("cms_http_request_duration_seconds", "Duration of HTTP requests in seconds.",
+		metrics.DefaultBuckets, "route", "method", "status")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := newStatusRecorder(w)
+			next.ServeHTTP(recorder, r)
+
+			route := unmatchedRoute
+			if _, pattern := routes.Handler(r); pattern != "" {
+				route = pattern
+			}
+			method := methodLabel(r.Method)
+			status := strconv.Itoa(recorder.status)
+			requests.Inc(route, method, status)
+			duration.Observe(time.Since(start).Seconds(), route, method, status)
+		})
+	}
+}
+
+// methodLabel returns the method of a request as a label, mapping non-standard methods to otherMethod
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+		http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethod
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !integration
+
+package middleware
+
+import (
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	reg := metrics.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/content/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "0" {
+			http.Error(w, "content not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	handler := MetricsMiddleware(reg, mux)(mux)
+
+	for _, path := range []string{"/content/1", "/content/2", "/content/0", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	for _, method := range []string{"PURGE", "get", "X-RANDOM-1"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/content/1", nil))
+	}
+
+	var b strings.Builder
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	for _, line := range []string{
+		`cms_http_requests_total{route="/content/{id}",method="GET",status="200"} 2`,
+		`cms_http_requests_total{route="/content/{id}",method="GET",status="404"} 1`,
+		`cms_http_requests_total{route="unmatched",method="GET",status="404"} 1`,
+		`cms_http_request_duration_seconds_count{route="/content/{id}",method="GET",status="200"} 2`,
+		`cms_http_requests_total{route="/content/{id}",method="other",status="200"} 3`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("WriteTo() got = \n%s\nexpected to contain %s", b.String(), line)
+		}
+	}
+	for _, method := range []string{"PURGE", "get", "X-RANDOM-1"} {
+		if strings.Contains(b.String(), `method="`+method+`"`) {
+			t.Errorf("WriteTo() got = \n%s\nexpected no series for method %s", b.String(), method)
+		}
+	}
+}
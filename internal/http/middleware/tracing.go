@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"net/http"
+)
+
+// TracingMiddleware starts a span for every request, continuing the trace of an incoming traceparent header and
+// returning the traceparent of the span in the response. Spans are named by the method and the pattern of the route
+// in routes that matches the request.
+func TracingMiddleware(tracer *tracing.Tracer, routes *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := unmatchedRoute
+			if _, pattern := routes.Handler(r); pattern != "" {
+				route = pattern
+			}
+
+			parent, _ := tracing.Extract(r.Header)
+			ctx, span := tracer.Start(r.Context(), r.Method+" "+route, parent)
+			defer span.End()
+			span.SetAttribute("http.request.method", r.Method)
+			span.SetAttribute("http.route", route)
+			span.SetAttribute("url.path", r.URL.Path)
+			tracing.Inject(ctx, w.Header())
+
+			recorder := newStatusRecorder(w)
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			span.SetAttribute("http.response.status_code", recorder.status)
+			if recorder.status >= http.StatusInternalServerError {
+				span.SetError(fmt.Errorf("%d %s", recorder.status, http.StatusText(recorder.status)))
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+//go:build !integration
+
+package middleware
+
+import (
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []tracing.SpanData
+}
+
+func (e *recordingExporter) ExportSpan(span tracing.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	exporter := &recordingExporter{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/content/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "Service.GetContentByID")
+		span.End()
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	})
+	handler := TracingMiddleware(tracing.NewTracer(exporter), mux)(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/content/1", nil)
+	req.Header.Set(tracing.TraceparentHeader, traceparent)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("exported spans got = %d, expected = 2", len(exporter.spans))
+	}
+	child, root := exporter.spans[0], exporter.spans[1]
+	if root.Name != "GET /content/{id}" {
+		t.Errorf("root span name got = %s, expected = GET /content/{id}", root.Name)
+	}
+	if root.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		root.ParentSpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("root span got = %+v, expected to continue the incoming trace", root.SpanContext)
+	}
+	if child.ParentSpanID != root.SpanContext.SpanID {
+		t.Errorf("child parent span ID got = %s, expected = %s", child.ParentSpanID, root.SpanContext.SpanID)
+	}
+	if root.Attributes["http.response.status_code"] != http.StatusInternalServerError || root.Err == nil {
+		t.Errorf("root span got = %+v, expected the failed status", root)
+	}
+	if got := rec.Header().Get(tracing.TraceparentHeader); got != root.SpanContext.Traceparent() {
+		t.Errorf("response traceparent got = %s, expected = %s", got, root.SpanContext.Traceparent())
+	}
+}
@@ -6,10 +6,30 @@ type CreateContent struct {
 	CreationDate string `json:"created_at"`
 }
 
+type UpdateContent struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	LastModifiedDate string `json:"updated_at"`
+}
+
+type DeleteContent struct {
+	ID        int  `json:"id"`
+	Permanent bool `json:"permanent"`
+}
+
+type ListContent struct {
+	Content    []GetContent `json:"content"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
 type GetContent struct {
 	ID          int       `json:"id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Status      string    `json:"status,omitempty"`
+	PublishAt   string    `json:"publish_at,omitempty"`
+	UnpublishAt string    `json:"unpublish_at,omitempty"`
+	DeletedAt   string    `json:"deleted_at,omitempty"`
 	Details     []Details `json:"details"`
 }
 
@@ -17,3 +37,34 @@ type Details struct {
 	ContentType string `json:"content_type"`
 	Value       string `json:"value"`
 }
+
+type ContentType struct {
+	ID    int        `json:"id"`
+	Name  string     `json:"name"`
+	Rules ValueRules `json:"rules"`
+}
+
+type ValueRules struct {
+	MaxLength int      `json:"max_length,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	MimeTypes []string `json:"mime_types,omitempty"`
+}
+
+type SearchResult struct {
+	GetContent
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+type Revision struct {
+	Revision  int         `json:"revision"`
+	Author    string      `json:"author,omitempty"`
+	CreatedAt string      `json:"created_at"`
+	Content   *GetContent `json:"content,omitempty"`
+}
+
+type FieldChange struct {
+	Field string  `json:"field"`
+	From  *string `json:"from"`
+	To    *string `json:"to"`
+}
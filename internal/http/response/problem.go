@@ -0,0 +1,83 @@
+package response
+
+import (
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Code and Fields are extension members carrying the machine-readable
+// error code and the invalid request fields of a fail response.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+// WantsProblem reports whether the client accepts problem details, in which case errors are returned in that format
+// instead of the default JSend envelope
+func WantsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue // Explicitly not acceptable
+		}
+		return true
+	}
+	return false
+}
+
+// newProblem builds the problem details of a failed request. The problem type is left as "about:blank", so the
+// title is the standard reason phrase of the status code and the code member identifies the error.
+func newProblem(r *http.Request, status int, detail string) Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+	if r != nil && r.URL != nil {
+		problem.Instance = r.URL.Path
+	}
+	return problem
+}
+
+// failProblem converts the data of a fail response into problem details
+func failProblem(r *http.Request, data interface{}, status int) Problem {
+	switch d := data.(type) {
+	case FailDetail:
+		problem := newProblem(r, status, d.Message)
+		problem.Code = d.Code
+		problem.Fields = d.Fields
+		return problem
+	case string:
+		return newProblem(r, status, d)
+	default:
+		return newProblem(r, status, "")
+	}
+}
+
+func writeProblem(w http.ResponseWriter, logger *slog.Logger, problem Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logger.Error("failed to encode problem details into JSON", "error", err)
+	}
+}
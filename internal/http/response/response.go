@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"github.com/g-stro/content-management-service/internal/logging"
 	"log/slog"
 	"net/http"
 )
@@ -31,14 +32,24 @@ type FailResponse struct {
 	Data interface{} `json:"data"`
 }
 
+// FailDetail is the data of a fail response caused by a domain error, identified by a machine-readable code
+type FailDetail struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"` // Invalid request fields and the reason each was rejected
+}
+
 // ErrorResponse is for requests that failed due to server error
 type ErrorResponse struct {
 	BaseResponse
 	Message string `json:"message"`
 }
 
-func HttpSuccess(w http.ResponseWriter, data interface{}, status int, logMsg string) {
-	slog.Info(logMsg, "data", data)
+// HttpSuccess writes a success response, logging it at debug level with the logger of the request. The response
+// data is only logged when payload logging is enabled, as it may be large and contain unpublished content.
+func HttpSuccess(w http.ResponseWriter, r *http.Request, data interface{}, status int, logMsg string) {
+	logger := requestLogger(r)
+	logger.Debug(logMsg, logging.Payload("data", data))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	err := json.NewEncoder(w).Encode(SuccessResponse{
@@ -46,12 +57,19 @@ func HttpSuccess(w http.ResponseWriter, data interface{}, status int, logMsg str
 		Data:         data,
 	})
 	if err != nil {
-		slog.Error("failed to encode successful response data into JSON", "error", err)
+		logger.Error("failed to encode successful response data into JSON", "error", err)
 	}
 }
 
-func HttpFail(w http.ResponseWriter, data interface{}, status int, logMsg string) {
-	slog.Error(logMsg, "data", data)
+// HttpFail writes a fail response, as problem details if the request accepts them
+func HttpFail(w http.ResponseWriter, r *http.Request, data interface{}, status int, logMsg string) {
+	logger := requestLogger(r)
+	logger.Error(logMsg, "data", data)
+	if WantsProblem(r) {
+		writeProblem(w, logger, failProblem(r, data, status))
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	err := json.NewEncoder(w).Encode(FailResponse{
@@ -59,14 +77,14 @@ func HttpFail(w http.ResponseWriter, data interface{}, status int, logMsg string
 		Data:         data,
 	})
 	if err != nil {
-		slog.Error("failed to encode failure response data into JSON", "error", err)
+		logger.Error("failed to encode failure response data into JSON", "error", err)
 	}
 }
 
-func HttpError(w http.ResponseWriter, err error, status int, logMsg string) {
-	slog.Error(logMsg, "error", err)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+// HttpError writes an error response, as problem details if the request accepts them
+func HttpError(w http.ResponseWriter, r *http.Request, err error, status int, logMsg string) {
+	logger := requestLogger(r)
+	logger.Error(logMsg, "error", err)
 
 	var errorMessage string
 	if err == nil {
@@ -75,11 +93,27 @@ func HttpError(w http.ResponseWriter, err error, status int, logMsg string) {
 		errorMessage = err.Error()
 	}
 
+	if WantsProblem(r) {
+		writeProblem(w, logger, newProblem(r, status, errorMessage))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
 	encodeErr := json.NewEncoder(w).Encode(ErrorResponse{
 		BaseResponse: BaseResponse{Status: Error},
 		Message:      errorMessage,
 	})
 	if encodeErr != nil {
-		slog.Error("failed to encode error response data into JSON", "error", encodeErr)
+		logger.Error("failed to encode error response data into JSON", "error", encodeErr)
+	}
+}
+
+// requestLogger returns the request-scoped logger of r, or the default logger without a request
+func requestLogger(r *http.Request) *slog.Logger {
+	if r == nil {
+		return slog.Default()
 	}
+	return logging.FromContext(r.Context())
 }
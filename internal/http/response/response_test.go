@@ -47,7 +47,7 @@ func TestHttpSuccess(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
-			HttpSuccess(w, tt.data, tt.status, tt.logMsg)
+			HttpSuccess(w, nil, tt.data, tt.status, tt.logMsg)
 
 			resp := w.Result()
 			defer resp.Body.Close()
@@ -105,7 +105,7 @@ func TestHttpFail(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
-			HttpFail(w, tt.data, tt.status, tt.logMsg)
+			HttpFail(w, nil, tt.data, tt.status, tt.logMsg)
 
 			resp := w.Result()
 			defer resp.Body.Close()
@@ -163,7 +163,7 @@ func TestHttpError(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
-			HttpError(w, tt.err, tt.status, tt.logMsg)
+			HttpError(w, nil, tt.err, tt.status, tt.logMsg)
 
 			resp := w.Result()
 			defer resp.Body.Close()
@@ -184,3 +184,104 @@ func TestHttpError(t *testing.T) {
 		})
 	}
 }
+
+func TestWantsProblem(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected bool
+	}{
+		{name: "no accept header", accept: "", expected: false},
+		{name: "json", accept: "application/json", expected: false},
+		{name: "problem json", accept: "application/problem+json", expected: true},
+		{name: "among other types", accept: "application/json, application/problem+json;q=0.9", expected: true},
+		{name: "not acceptable", accept: "application/problem+json;q=0", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/content", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			if got := WantsProblem(r); got != tt.expected {
+				t.Errorf("WantsProblem() got = %v, expected = %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHttpFail_Problem(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		status   int
+		wantBody Problem
+	}{
+		{
+			name: "fail detail",
+			data: FailDetail{Code: "validation_failed", Message: "validation failed",
+				Fields: map[string]string{"details[0].value": "must be at most 5 characters"}},
+			status: http.StatusUnprocessableEntity,
+			wantBody: Problem{Type: "about:blank", Title: "Unprocessable Entity", Status: http.StatusUnprocessableEntity,
+				Detail: "validation failed", Instance: "/content/1", Code: "validation_failed",
+				Fields: map[string]string{"details[0].value": "must be at most 5 characters"}},
+		},
+		{
+			name:   "string data",
+			data:   "failure reason",
+			status: http.StatusBadRequest,
+			wantBody: Problem{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest,
+				Detail: "failure reason", Instance: "/content/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/content/1", nil)
+			r.Header.Set("Accept", ProblemContentType)
+			HttpFail(w, r, tt.data, tt.status, "Failure occurred")
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("expected content type %s, got %s", ProblemContentType, ct)
+			}
+
+			var responseBody Problem
+			if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+				t.Fatalf("failed to decode response body: %v", err)
+			}
+
+			if !reflect.DeepEqual(responseBody, tt.wantBody) {
+				t.Errorf("expected body %v, got %v", tt.wantBody, responseBody)
+			}
+		})
+	}
+}
+
+func TestHttpError_Problem(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/content", nil)
+	r.Header.Set("Accept", ProblemContentType)
+	HttpError(w, r, errors.New("something went wrong"), http.StatusInternalServerError, "Error occurred")
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	var responseBody Problem
+	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+
+	wantBody := Problem{Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+		Detail: "something went wrong", Instance: "/content"}
+	if !reflect.DeepEqual(responseBody, wantBody) {
+		t.Errorf("expected body %v, got %v", wantBody, responseBody)
+	}
+}
@@ -0,0 +1,188 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Log formats selectable with LOG_FORMAT
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Redacted replaces the values of sensitive attributes, payload fields and headers
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are the attribute, payload field and header names whose values are always redacted. Names are
+// matched case-insensitively, with hyphens and underscores treated alike.
+var DefaultRedactKeys = []string{
+	"authorization", "proxy_authorization", "cookie", "set_cookie", "x_api_key", "api_key", "password", "secret",
+	"token", "access_token", "refresh_token",
+}
+
+// Options configures the logger returned by New
+type Options struct {
+	Level  slog.Leveler
+	Format string // FormatText or FormatJSON, FormatText if empty
+	// Payloads enables logging attributes created with Payload, which are dropped otherwise
+	Payloads bool
+	// RedactKeys are redacted in addition to DefaultRedactKeys
+	RedactKeys []string
+}
+
+// New returns a logger writing to w in the configured format, which redacts sensitive values and drops payloads
+// unless they are enabled
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", opts.Format)
+	}
+
+	redactKeys := make(map[string]bool)
+	for _, key := range slices.Concat(DefaultRedactKeys, opts.RedactKeys) {
+		if key = normalizeKey(key); key != "" {
+			redactKeys[key] = true
+		}
+	}
+	return slog.New(&redactingHandler{next: handler, payloads: opts.Payloads, redactKeys: redactKeys}), nil
+}
+
+// payload is the value of a Payload attribute. Handlers other than the one returned by New resolve it to an empty
+// group, which they omit, so payloads are never logged unless explicitly enabled.
+type payload struct {
+	value any
+}
+
+func (p payload) LogValue() slog.Value {
+	return slog.GroupValue()
+}
+
+// Payload returns an attribute holding request or response data, such as content, that is only logged when payload
+// logging is enabled. Logged payloads are converted to JSON values, with sensitive fields redacted.
+func Payload(key string, value any) slog.Attr {
+	return slog.Any(key, payload{value: value})
+}
+
+// redactingHandler redacts the attributes of records and drops disabled payloads before passing them to next
+type redactingHandler struct {
+	next       slog.Handler
+	payloads   bool
+	redactKeys map[string]bool
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		redacted = append(redacted, h.redact(a))
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted), payloads: h.payloads, redactKeys: h.redactKeys}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), payloads: h.payloads, redactKeys: h.redactKeys}
+}
+
+// redact returns the attribute with sensitive values replaced. Disabled payloads become empty attributes, which
+// handlers omit.
+func (h *redactingHandler) redact(a slog.Attr) slog.Attr {
+	if h.redactKeys[normalizeKey(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		members := a.Value.Group()
+		redacted := make([]slog.Attr, 0, len(members))
+		for _, member := range members {
+			redacted = append(redacted, h.redact(member))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindLogValuer:
+		if p, ok := a.Value.Any().(payload); ok {
+			if !h.payloads {
+				return slog.Attr{}
+			}
+			return slog.Any(a.Key, h.redactPayload(p.value))
+		}
+	case slog.KindAny:
+		if header, ok := a.Value.Any().(http.Header); ok {
+			return slog.Any(a.Key, h.redactHeader(header))
+		}
+	}
+	return a
+}
+
+// redactPayload converts a payload into its JSON value and redacts its sensitive fields at any depth
+func (h *redactingHandler) redactPayload(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("!ERROR:%v", err) // Like the handlers of log/slog when a value cannot be formatted
+	}
+	var decoded any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return fmt.Sprintf("!ERROR:%v", err)
+	}
+	return h.redactJSON(decoded)
+}
+
+func (h *redactingHandler) redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if h.redactKeys[normalizeKey(key)] {
+				v[key] = Redacted
+			} else {
+				v[key] = h.redactJSON(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = h.redactJSON(item)
+		}
+	}
+	return value
+}
+
+// redactHeader returns a copy of header with the values of sensitive headers replaced
+func (h *redactingHandler) redactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if h.redactKeys[normalizeKey(name)] {
+			redacted[name] = []string{Redacted}
+		} else {
+			redacted[name] = values
+		}
+	}
+	return redacted
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+}
@@ -0,0 +1,112 @@
+//go:build !integration
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testPayload struct {
+	Name   string            `json:"name"`
+	Token  string            `json:"token"`
+	Nested map[string]string `json:"nested"`
+}
+
+func TestNew(t *testing.T) {
+	header := http.Header{"Authorization": {"Bearer secret-key"}, "X-User": {"alice"}, "X-Session": {"abc"}}
+	data := testPayload{Name: "Article", Token: "t0ken", Nested: map[string]string{"Password": "hunter2", "a": "b"}}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected map[string]any
+	}{
+		{
+			name: "payloads disabled",
+			opts: Options{Format: FormatJSON},
+			expected: map[string]any{
+				"api_key": Redacted,
+				"headers": map[string]any{"Authorization": []any{Redacted}, "X-User": []any{"alice"},
+					"X-Session": []any{"abc"}},
+				"group": map[string]any{"password": Redacted, "user": "alice"},
+			},
+		},
+		{
+			name: "payloads enabled with extra redacted keys",
+			opts: Options{Format: FormatJSON, Payloads: true, RedactKeys: []string{"X-Session", " name "}},
+			expected: map[string]any{
+				"api_key": Redacted,
+				"headers": map[string]any{"Authorization": []any{Redacted}, "X-User": []any{"alice"},
+					"X-Session": []any{Redacted}},
+				"group": map[string]any{"password": Redacted, "user": "alice"},
+				"data": map[string]any{"name": Redacted, "token": Redacted,
+					"nested": map[string]any{"Password": Redacted, "a": "b"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(&buf, tt.opts)
+			if err != nil {
+				t.Fatalf("New() unexpected error: %v", err)
+			}
+
+			logger.With("api_key", "k").Info("test",
+				slog.Any("headers", header),
+				slog.Group("group", "password", "p", "user", "alice"),
+				Payload("data", data),
+			)
+
+			var line map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+				t.Fatalf("failed to decode log line %s: %v", buf.String(), err)
+			}
+			for _, key := range []string{"time", "level", "msg"} {
+				delete(line, key)
+			}
+			got, _ := json.Marshal(line)
+			expected, _ := json.Marshal(tt.expected)
+			if string(got) != string(expected) {
+				t.Errorf("log line got = %s, expected = %s", got, expected)
+			}
+			if header.Get("Authorization") != "Bearer secret-key" {
+				t.Error("redacting modified the logged header")
+			}
+		})
+	}
+}
+
+func TestNew_LevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Options{Level: slog.LevelWarn, Format: FormatText})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	if got := buf.String(); strings.Contains(got, "hidden") || !strings.Contains(got, "level=WARN msg=shown") {
+		t.Errorf("log output got = %q, expected only the warning in text format", got)
+	}
+
+	if _, err := New(&buf, Options{Format: "xml"}); err == nil {
+		t.Error("New() expected an error for an invalid format")
+	}
+}
+
+func TestPayload_DefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("test", Payload("data", testPayload{Name: "Article"}))
+
+	if strings.Contains(buf.String(), "Article") {
+		t.Errorf("log line got = %s, expected the payload to be omitted", buf.String())
+	}
+}
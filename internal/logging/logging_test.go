@@ -0,0 +1,21 @@
+//go:build !integration
+
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	if got := FromContext(WithLogger(context.Background(), logger)); got != logger {
+		t.Errorf("FromContext() got = %v, expected = %v", got, logger)
+	}
+	if got := FromContext(context.Background()); got != slog.Default() {
+		t.Errorf("FromContext() got = %v, expected the default logger", got)
+	}
+}
@@ -0,0 +1,50 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidDocument is returned when either the original document or the patch is not valid JSON
+var ErrInvalidDocument = errors.New("invalid JSON document")
+
+// Apply applies a JSON Merge Patch (RFC 7386) to the original document and returns the patched document
+func Apply(original, patch []byte) ([]byte, error) {
+	var target interface{}
+	if len(original) > 0 {
+		if err := json.Unmarshal(original, &target); err != nil {
+			return nil, errors.Join(ErrInvalidDocument, err)
+		}
+	}
+
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, errors.Join(ErrInvalidDocument, err)
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+// merge recursively merges the patch into the target as described by RFC 7386
+func merge(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		// A non-object patch replaces the target entirely
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{})
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key) // A null value removes the member
+			continue
+		}
+		targetObj[key] = merge(targetObj[key], value)
+	}
+
+	return targetObj
+}
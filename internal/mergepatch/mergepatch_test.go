@@ -0,0 +1,60 @@
+//go:build !integration
+
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestApply uses the examples from RFC 7386 Appendix A
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		patch    string
+		expected string
+	}{
+		{name: "replace member", original: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{name: "add member", original: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+		{name: "remove member", original: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+		{name: "remove one of many", original: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+		{name: "replace array", original: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{name: "replace with array", original: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+		{name: "nested merge", original: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+		{name: "arrays are not merged", original: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+		{name: "non-object patch", original: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+		{name: "null patch", original: `{"a":"foo"}`, patch: `null`, expected: `null`},
+		{name: "non-object target", original: `["a","b"]`, patch: `{"a":"b"}`, expected: `{"a":"b"}`},
+		{name: "nested null is not added", original: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Apply([]byte(tt.original), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply() unexpected error = %v", err)
+			}
+
+			var got, expected interface{}
+			if err := json.Unmarshal(result, &got); err != nil {
+				t.Fatalf("failed to decode result: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.expected), &expected); err != nil {
+				t.Fatalf("failed to decode expected: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Apply() got = %s, expected = %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApply_InvalidDocument(t *testing.T) {
+	if _, err := Apply([]byte(`{"a":"b"}`), []byte(`{"a":`)); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Apply() error = %v, expected = %v", err, ErrInvalidDocument)
+	}
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import "database/sql"
+
+// RegisterDBStats registers gauges and counters of the connection pool of db, read from db.Stats() on every scrape
+func RegisterDBStats(r *Registry, db *sql.DB) {
+	r.NewGaugeFunc("cms_db_max_open_connections", "Maximum number of open connections to the database.",
+		func() float64 { return float64(db.Stats().MaxOpenConnections) })
+	r.NewGaugeFunc("cms_db_open_connections", "Number of established connections, in use and idle.",
+		func() float64 { return float64(db.Stats().OpenConnections) })
+	r.NewGaugeFunc("cms_db_in_use_connections", "Number of connections currently in use.",
+		func() float64 { return float64(db.Stats().InUse) })
+	r.NewGaugeFunc("cms_db_idle_connections", "Number of idle connections.",
+		func() float64 { return float64(db.Stats().Idle) })
+	r.NewCounterFunc("cms_db_wait_count_total", "Total number of connections waited for.",
+		func() float64 { return float64(db.Stats().WaitCount) })
+	r.NewCounterFunc("cms_db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		func() float64 { return db.Stats().WaitDuration.Seconds() })
+	r.NewCounterFunc("cms_db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.",
+		func() float64 { return float64(db.Stats().MaxIdleClosed) })
+	r.NewCounterFunc("cms_db_max_lifetime_closed_total",
+		"Total number of connections closed due to SetConnMaxLifetime.",
+		func() float64 { return float64(db.Stats().MaxLifetimeClosed) })
+}
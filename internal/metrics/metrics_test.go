@@ -0,0 +1,147 @@
+//go:build !integration
+
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounter("test_requests_total", "Total requests.", "method", "path")
+	duration := reg.NewHistogram("test_duration_seconds", "Request duration.", []float64{1, 0.1}, "method")
+	reg.NewGaugeFunc("test_connections", "Open connections.", func() float64 { return 3 })
+
+	requests.Inc("GET", "/a")
+	requests.Add(2, "GET", "/a")
+	requests.Inc("POST", `/"quoted"\path`)
+	requests.Add(-1, "GET", "/a") // Counters never decrease
+	duration.Observe(0.05, "GET")
+	duration.Observe(0.5, "GET")
+	duration.Observe(2, "GET")
+
+	var b strings.Builder
+	n, err := reg.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	if n != int64(b.Len()) {
+		t.Errorf("WriteTo() got = %d bytes, expected = %d", n, b.Len())
+	}
+
+	expected := `# HELP test_connections Open connections.
+# TYPE test_connections gauge
+test_connections 3
+# HELP test_duration_seconds Request duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="GET",le="0.1"} 1
+test_duration_seconds_bucket{method="GET",le="1"} 2
+test_duration_seconds_bucket{method="GET",le="+Inf"} 3
+test_duration_seconds_sum{method="GET"} 2.55
+test_duration_seconds_count{method="GET"} 3
+# HELP test_requests_total Total requests.
+# TYPE test_requests_total counter
+test_requests_total{method="GET",path="/a"} 3
+test_requests_total{method="POST",path="/\"quoted\"\\path"} 1
+`
+	if got := b.String(); got != expected {
+		t.Errorf("WriteTo() got = \n%s\nexpected = \n%s", got, expected)
+	}
+}
+
+func TestRegistry_NilMetricsDiscardUpdates(t *testing.T) {
+	var counter *Counter
+	var histogram *Histogram
+
+	// Must not panic
+	counter.Inc("a")
+	histogram.Observe(1, "a")
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(reg *Registry)
+	}{
+		{
+			name: "duplicate name",
+			call: func(reg *Registry) {
+				reg.NewCounter("test_total", "Test.")
+				reg.NewHistogram("test_total", "Test.", DefaultBuckets)
+			},
+		},
+		{
+			name: "label count mismatch",
+			call: func(reg *Registry) {
+				reg.NewCounter("test_total", "Test.", "method").Inc("GET", "/a")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			tt.call(NewRegistry())
+		})
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounter("test_total", "Test.").Inc()
+
+	tests := []struct {
+		method       string
+		expectedCode int
+	}{
+		{method: http.MethodGet, expectedCode: http.StatusOK},
+		{method: http.MethodPost, expectedCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			reg.Handler().ServeHTTP(rec, httptest.NewRequest(tt.method, "/metrics", nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("%s /metrics status got = %d, expected = %d", tt.method, rec.Code, tt.expectedCode)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != ContentType {
+				t.Errorf("Content-Type got = %s, expected = %s", got, ContentType)
+			}
+			if !strings.Contains(rec.Body.String(), "test_total 1\n") {
+				t.Errorf("body got = %s, expected the test_total counter", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRegisterDBStats(t *testing.T) {
+	reg := NewRegistry()
+	db := sql.OpenDB(nil) // Never connects, so the pool is empty
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+	RegisterDBStats(reg, db)
+
+	var b strings.Builder
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	for _, line := range []string{"cms_db_max_open_connections 7\n", "cms_db_open_connections 0\n",
+		"# TYPE cms_db_wait_count_total counter\n"} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("WriteTo() got = \n%s\nexpected to contain %q", b.String(), line)
+		}
+	}
+}
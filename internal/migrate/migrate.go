@@ -0,0 +1,379 @@
+// Package migrate applies versioned schema migrations to the database. Applied versions are recorded in the
+// schema_migrations table. On Postgres an advisory lock ensures only one instance migrates at a time, while SQLite
+// relies on its database-wide write lock, taken by every migration transaction.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/database"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrUnknownVersion is returned when a version has no migration, for example because the database was migrated by a
+// newer build
+var ErrUnknownVersion = errors.New("unknown migration version")
+
+// lockKey identifies the advisory lock held while migrating. It is shared by every instance of the service.
+const lockKey int64 = 0x636d735f6d6967 // "cms_mig"
+
+// fileNamePattern matches migration files such as 0001_initial_schema.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change and the statements that revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied to the database
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load reads the migrations of a file system, ordered by version. Every version must have an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		contents, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+
+	return migrations, nil
+}
+
+// DefaultTable is the table that records the applied migration versions
+const DefaultTable = "schema_migrations"
+
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+	table      string
+}
+
+// New creates a migrator for the migrations of a file system, run against a database of the given driver
+func New(db *sql.DB, driver string, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, driver: driver, migrations: migrations, table: DefaultTable}, nil
+}
+
+// Latest returns the highest known migration version, or 0 if there are none
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration and returns the number applied
+func (m *Migrator) Up() (int, error) {
+	return m.Goto(m.Latest())
+}
+
+// Down reverts the given number of most recently applied migrations and returns the number reverted
+func (m *Migrator) Down(steps int) (int, error) {
+	if steps <= 0 {
+		return 0, nil
+	}
+
+	count := 0
+	err := m.withLock(func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(context.Background(), conn)
+		if err != nil {
+			return err
+		}
+
+		versions := sortedVersions(applied)
+		slices.Reverse(versions)
+		for _, version := range versions[:min(steps, len(versions))] {
+			if err := m.revert(conn, version); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Goto migrates the database up or down to the given version and returns the number of migrations run. Version 0
+// reverts every migration.
+func (m *Migrator) Goto(version int) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	count := 0
+	err := m.withLock(func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(context.Background(), conn)
+		if err != nil {
+			return err
+		}
+
+		// Revert newer migrations first, latest to oldest
+		versions := sortedVersions(applied)
+		slices.Reverse(versions)
+		for _, v := range versions {
+			if v <= version {
+				break
+			}
+			if err := m.revert(conn, v); err != nil {
+				return err
+			}
+			count++
+		}
+
+		// Then apply pending migrations, including any that were skipped below the target
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Status lists every known migration and when it was applied
+func (m *Migrator) Status() ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(func(conn *sql.Conn) error {
+		applied, err := m.appliedVersions(context.Background(), conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Pending returns the number of known migrations that have not been applied. Unlike Status it does not take the
+// migration lock, so it is cheap enough for health checks, and fails if no migration has ever been applied.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	applied, err := m.appliedVersions(ctx, m.db)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// withLock runs fn on a dedicated connection while holding the migration advisory lock. The lock is bound to the
+// session, so every statement must run on the same connection. SQLite has no advisory locks; its migrations are
+// serialized by the write lock of each transaction instead.
+func (m *Migrator) withLock(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		slog.Error("failed to acquire a database connection", "error", err)
+		return err
+	}
+	defer conn.Close()
+
+	if m.driver != database.DriverSQLite {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			slog.Error("failed to acquire the migration lock", "error", err)
+			return err
+		}
+		defer func() {
+			if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+				slog.Error("failed to release the migration lock", "error", err)
+			}
+		}()
+	}
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+m.table+` (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT      NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`)
+	if err != nil {
+		slog.Error("failed to create the migrations table", "table", m.table, "error", err)
+		return err
+	}
+
+	return fn(conn)
+}
+
+// apply runs an up migration and records it, in a single transaction
+func (m *Migrator) apply(conn *sql.Conn, migration Migration) error {
+	err := inTx(conn, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT INTO "+m.table+" (version, name, applied_at) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		slog.Error("failed to apply migration", "version", migration.Version, "name", migration.Name, "error", err)
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	slog.Info("applied migration", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+// revert runs the down migration of an applied version and removes its record, in a single transaction
+func (m *Migrator) revert(conn *sql.Conn, version int) error {
+	migration := m.find(version)
+	if migration == nil {
+		return fmt.Errorf("%w: %d is applied but has no down migration", ErrUnknownVersion, version)
+	}
+
+	err := inTx(conn, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM "+m.table+" WHERE version = $1", version)
+		return err
+	})
+	if err != nil {
+		slog.Error("failed to revert migration", "version", migration.Version, "name", migration.Name, "error", err)
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	slog.Info("reverted migration", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// querier is implemented by *sql.DB and *sql.Conn
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// appliedVersions returns the applied migration versions and when each was applied
+func (m *Migrator) appliedVersions(ctx context.Context, q querier) (map[int]time.Time, error) {
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM "+m.table)
+	if err != nil {
+		slog.Error("failed to fetch applied migrations", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			slog.Error("failed to scan applied migration", "error", err)
+			return nil, err
+		}
+		applied[version] = appliedAt.UTC()
+	}
+
+	return applied, rows.Err()
+}
+
+func sortedVersions(applied map[int]time.Time) []int {
+	versions := make([]int, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// inTx runs fn in a transaction on the connection, committing if it succeeds
+func inTx(conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.Error("failed to roll back transaction", "error", rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package migrate
+
+import (
+	"github.com/g-stro/content-management-service/database"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrator(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	migrator, err := New(conn.DB, conn.Driver, fstest.MapFS{
+		"0001_create_widgets.up.sql":   {Data: []byte(`CREATE TABLE migrate_test_widgets ("id" SERIAL PRIMARY KEY);`)},
+		"0001_create_widgets.down.sql": {Data: []byte(`DROP TABLE migrate_test_widgets;`)},
+		"0002_add_name.up.sql":         {Data: []byte(`ALTER TABLE migrate_test_widgets ADD COLUMN "name" TEXT;`)},
+		"0002_add_name.down.sql":       {Data: []byte(`ALTER TABLE migrate_test_widgets DROP COLUMN "name";`)},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	// Keep the test apart from the schema of the service
+	migrator.table = "migrate_test_migrations"
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DROP TABLE IF EXISTS migrate_test_widgets; DROP TABLE IF EXISTS migrate_test_migrations;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	steps := []struct {
+		name     string
+		run      func() (int, error)
+		expected int
+		applied  []bool
+	}{
+		{name: "up", run: migrator.Up, expected: 2, applied: []bool{true, true}},
+		{name: "up again", run: migrator.Up, expected: 0, applied: []bool{true, true}},
+		{name: "down one", run: func() (int, error) { return migrator.Down(1) }, expected: 1, applied: []bool{true, false}},
+		{name: "goto latest", run: func() (int, error) { return migrator.Goto(2) }, expected: 1, applied: []bool{true, true}},
+		{name: "goto zero", run: func() (int, error) { return migrator.Goto(0) }, expected: 2, applied: []bool{false, false}},
+	}
+
+	for _, step := range steps {
+		n, err := step.run()
+		if err != nil || n != step.expected {
+			t.Fatalf("%s got = %d, error = %v, expected = %d", step.name, n, err, step.expected)
+		}
+
+		statuses, err := migrator.Status()
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		for i, status := range statuses {
+			if (status.AppliedAt != nil) != step.applied[i] {
+				t.Errorf("after %s migration %d applied = %v, expected = %v",
+					step.name, status.Version, status.AppliedAt != nil, step.applied[i])
+			}
+		}
+	}
+
+	if _, err := migrator.Goto(3); err == nil {
+		t.Errorf("Goto() of unknown version expected an error")
+	}
+}
@@ -0,0 +1,158 @@
+//go:build !integration
+
+package migrate
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/database"
+	"github.com/g-stro/content-management-service/database/migrations"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		fsys      fstest.MapFS
+		expected  []Migration
+		expectErr bool
+	}{
+		{
+			name: "ordered by version",
+			fsys: fstest.MapFS{
+				"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+				"0002_add_index.down.sql":    {Data: []byte("DROP INDEX")},
+				"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"0001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"README.md":                  {Data: []byte("ignored")},
+			},
+			expected: []Migration{
+				{Version: 1, Name: "create_table", Up: "CREATE TABLE", Down: "DROP TABLE"},
+				{Version: 2, Name: "add_index", Up: "CREATE INDEX", Down: "DROP INDEX"},
+			},
+		},
+		{
+			name: "missing down file",
+			fsys: fstest.MapFS{
+				"0001_create_table.up.sql": {Data: []byte("CREATE TABLE")},
+			},
+			expectErr: true,
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"0001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+				"0001_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+				"0001_add_index.down.sql":    {Data: []byte("DROP INDEX")},
+			},
+			expectErr: true,
+		},
+		{
+			name: "version zero",
+			fsys: fstest.MapFS{
+				"0000_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+				"0000_create_table.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Load(tt.fsys)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Load() error = %v, expectErr = %v", err, tt.expectErr)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Load() got = %v, expected = %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLoad_Embedded checks that the migrations shipped with the service are well-formed and that every database
+// driver has the same versions
+func TestLoad_Embedded(t *testing.T) {
+	var versions [][]int
+	for _, driver := range []string{database.DriverPostgres, database.DriverSQLite} {
+		fsys, err := migrations.ForDriver(driver)
+		if err != nil {
+			t.Fatalf("ForDriver(%q) error = %v", driver, err)
+		}
+		result, err := Load(fsys)
+		if err != nil {
+			t.Fatalf("Load() of %s error = %v", driver, err)
+		}
+
+		var driverVersions []int
+		for i, m := range result {
+			if m.Version != i+1 {
+				t.Errorf("%s migration %s has version %d, expected = %d", driver, m.Name, m.Version, i+1)
+			}
+			driverVersions = append(driverVersions, m.Version)
+		}
+		versions = append(versions, driverVersions)
+	}
+
+	if !reflect.DeepEqual(versions[0], versions[1]) {
+		t.Errorf("postgres versions = %v, sqlite versions = %v, expected them to match", versions[0], versions[1])
+	}
+}
+
+// TestMigrator_SQLite runs the embedded SQLite migrations up and down against a database file
+func TestMigrator_SQLite(t *testing.T) {
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "migrate.db"))
+
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.Close()
+
+	fsys, err := migrations.ForDriver(conn.Driver)
+	if err != nil {
+		t.Fatalf("ForDriver() error = %v", err)
+	}
+	migrator, err := New(conn.DB, conn.Driver, fsys)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := migrator.Pending(context.Background()); err == nil {
+		t.Errorf("Pending() expected an error before any migration was applied")
+	}
+	if n, err := migrator.Up(); err != nil || n != migrator.Latest() {
+		t.Fatalf("Up() got = %d, error = %v, expected = %d", n, err, migrator.Latest())
+	}
+	if n, err := migrator.Pending(context.Background()); err != nil || n != 0 {
+		t.Errorf("Pending() got = %d, error = %v, expected = 0", n, err)
+	}
+	var contentTypes int
+	if err := conn.DB.QueryRow("SELECT COUNT(*) FROM content_type").Scan(&contentTypes); err != nil || contentTypes != 3 {
+		t.Errorf("content types got = %d, error = %v, expected = 3", contentTypes, err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("migration %d is pending, expected it to be applied", status.Version)
+		}
+	}
+
+	if n, err := migrator.Goto(0); err != nil || n != migrator.Latest() {
+		t.Fatalf("Goto(0) got = %d, error = %v, expected = %d", n, err, migrator.Latest())
+	}
+	if n, err := migrator.Pending(context.Background()); err != nil || n != migrator.Latest() {
+		t.Errorf("Pending() got = %d, error = %v, expected = %d", n, err, migrator.Latest())
+	}
+	if _, err := conn.DB.Exec("SELECT 1 FROM content"); err == nil {
+		t.Errorf("content table still exists after reverting every migration")
+	}
+}
@@ -2,23 +2,75 @@ package model
 
 import "time"
 
+// ContentStatus is the editorial workflow state of content
+type ContentStatus string
+
+const (
+	StatusDraft     ContentStatus = "draft"
+	StatusReview    ContentStatus = "review"
+	StatusPublished ContentStatus = "published"
+	StatusArchived  ContentStatus = "archived"
+)
+
+// SchedulerAuthor is recorded as the author of changes made by the publishing scheduler
+const SchedulerAuthor = "scheduler"
+
+// Content is stored as JSON in revision snapshots. Its JSON keys are those of the first snapshots, which were written
+// before the fields had json tags, and must not change: stored snapshots are decoded with them and queried for them.
 type Content struct {
-	ID               int       `db:"id"`
-	Name             string    `db:"name"`
-	Description      string    `db:"description"`
-	CreationDate     time.Time `db:"creation_date"`
-	LastModifiedDate time.Time `db:"last_modified_date"`
-	Details          []*Details
+	ID               int           `db:"id" json:"ID"`
+	Name             string        `db:"name" json:"Name"`
+	Description      string        `db:"description" json:"Description"`
+	Status           ContentStatus `db:"status" json:"Status"`
+	CreationDate     time.Time     `db:"creation_date" json:"CreationDate"`
+	LastModifiedDate time.Time     `db:"last_modified_date" json:"LastModifiedDate"`
+	LastModifiedBy   string        `db:"last_modified_by" json:"LastModifiedBy"`
+	PublishAt        *time.Time    `db:"publish_at" json:"PublishAt"`
+	UnpublishAt      *time.Time    `db:"unpublish_at" json:"UnpublishAt"`
+	DeletedAt        *time.Time    `db:"deleted_at" json:"DeletedAt"`
+	Details          []*Details    `json:"Details"`
 }
 
+// Details are stored as JSON in revision snapshots, with JSON keys as stable as those of Content
 type Details struct {
-	ID            int    `db:"id"`
-	ContentID     int    `db:"content_id"`
-	ContentTypeID int    `db:"content_type_id"`
-	Value         string `db:"value"`
+	ID            int    `db:"id" json:"ID"`
+	ContentID     int    `db:"content_id" json:"ContentID"`
+	ContentTypeID int    `db:"content_type_id" json:"ContentTypeID"`
+	Value         string `db:"value" json:"Value"`
+	Position      int    `db:"position" json:"Position"` // Zero-based order of the detail within its content
+	// ContentTypeName is joined from the content type when details are read. It is not part of revision snapshots.
+	ContentTypeName string `db:"content_type_name" json:"-"`
 }
 
 type ContentType struct {
-	ID   int    `db:"id"`
-	Name string `db:"name"`
+	ID    int        `db:"id"`
+	Name  string     `db:"name"`
+	Rules ValueRules `db:"rules"`
+}
+
+// FormatURL requires detail values to be absolute http(s) URLs
+const FormatURL = "url"
+
+// ValueRules constrains the values of the content details of a content type. It is stored as JSON with the type.
+type ValueRules struct {
+	MaxLength int      `json:"max_length,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	MimeTypes []string `json:"mime_types,omitempty"` // Media types the URL must point to, e.g. "image/png" or "image/*"
+}
+
+// SearchResult is content matched by a full-text search, with its relevance and a highlighted excerpt
+type SearchResult struct {
+	Content *Content
+	Rank    float64
+	Snippet string
+}
+
+// Revision is an immutable snapshot of content and its details, written on every change
+type Revision struct {
+	ID        int       `db:"id"`
+	ContentID int       `db:"content_id"`
+	Revision  int       `db:"revision"`
+	Snapshot  *Content  `db:"snapshot"`
+	Author    string    `db:"author"`
+	CreatedAt time.Time `db:"created_at"`
 }
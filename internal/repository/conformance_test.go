@@ -0,0 +1,763 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/model"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// repositoryFactory returns an empty ContentRepository holding only the default content types.
+// It is called once per conformance subtest.
+type repositoryFactory func(t *testing.T) ContentRepository
+
+var conformanceTimestamp = time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// testRepositoryConformance runs the behavioral contract every ContentRepository implementation must satisfy:
+// read methods return a nil record and a nil error when nothing is found, write methods return ErrNotFound,
+// and content is returned in a stable order with its details grouped under it.
+func testRepositoryConformance(t *testing.T, newRepository repositoryFactory) {
+	t.Run("create and read", func(t *testing.T) {
+		testConformanceCreateAndRead(t, newRepository(t))
+	})
+	t.Run("ordering and details grouping", func(t *testing.T) {
+		testConformanceOrdering(t, newRepository(t))
+	})
+	t.Run("list content", func(t *testing.T) {
+		testConformanceListContent(t, newRepository(t))
+	})
+	t.Run("scheduled transitions", func(t *testing.T) {
+		testConformanceScheduledTransitions(t, newRepository(t))
+	})
+	t.Run("content without details and detail order", func(t *testing.T) {
+		testConformanceDetails(t, newRepository(t))
+	})
+	t.Run("content type lookups", func(t *testing.T) {
+		testConformanceContentTypes(t, newRepository(t))
+	})
+	t.Run("not found", func(t *testing.T) {
+		testConformanceNotFound(t, newRepository(t))
+	})
+	t.Run("conditional writes", func(t *testing.T) {
+		testConformanceConditionalWrites(t, newRepository(t))
+	})
+	t.Run("conditional writes with sub-microsecond times", func(t *testing.T) {
+		testConformanceSubMicrosecondTimes(t, newRepository(t))
+	})
+	t.Run("search snippets", func(t *testing.T) {
+		testConformanceSearchSnippets(t, newRepository(t))
+	})
+	t.Run("concurrency", func(t *testing.T) {
+		testConformanceConcurrency(t, newRepository(t))
+	})
+}
+
+func conformanceContent(name string, offset time.Duration, details ...*model.Details) *model.Content {
+	return &model.Content{
+		Name:             name,
+		Description:      name + " description",
+		CreationDate:     conformanceTimestamp.Add(offset),
+		LastModifiedDate: conformanceTimestamp.Add(offset),
+		LastModifiedBy:   "editor",
+		Details:          details,
+	}
+}
+
+func mustCreateContent(t *testing.T, repo ContentRepository, content *model.Content) *model.Content {
+	t.Helper()
+	created, err := repo.CreateContentWithDetails(context.Background(), content)
+	if err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+	return created
+}
+
+func testConformanceCreateAndRead(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	created := mustCreateContent(t, repo, conformanceContent("first", 0,
+		&model.Details{ContentTypeID: 1, Value: "hello"}, &model.Details{ContentTypeID: 2, Value: "https://a.b/c.png"}))
+	if created.ID <= 0 || created.Status != model.StatusDraft {
+		t.Fatalf("CreateContentWithDetails() got = %+v, expected a new ID in draft", created)
+	}
+	for _, d := range created.Details {
+		if d.ID <= 0 || d.ContentID != created.ID {
+			t.Errorf("CreateContentWithDetails() detail = %+v, expected an ID and content ID %d", d, created.ID)
+		}
+	}
+
+	got, err := repo.GetContentByID(ctx, created.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetContentByID() got = %v, %v, expected the created content", got, err)
+	}
+	if got.Name != "first" || got.Description != "first description" || got.Status != model.StatusDraft ||
+		got.LastModifiedBy != "editor" || !got.CreationDate.Equal(conformanceTimestamp) ||
+		!got.LastModifiedDate.Equal(conformanceTimestamp) || got.DeletedAt != nil {
+		t.Errorf("GetContentByID() got = %+v, expected the created fields", got)
+	}
+	if len(got.Details) != 2 || got.Details[0].Value != "hello" || got.Details[1].ContentTypeID != 2 {
+		t.Errorf("GetContentByID() details = %v, expected both created details in order", printSlice(got.Details))
+	}
+	if len(got.Details) == 2 && (got.Details[0].ContentTypeName != "text" || got.Details[1].ContentTypeName != "image") {
+		t.Errorf("GetContentByID() details = %v, expected the joined content type names", printSlice(got.Details))
+	}
+
+	revisions, err := repo.GetRevisions(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetRevisions() unexpected error: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Author != "editor" ||
+		revisions[0].Snapshot.Name != "first" || len(revisions[0].Snapshot.Details) != 2 {
+		t.Errorf("GetRevisions() got = %+v, expected the initial revision", revisions)
+	}
+}
+
+func testConformanceOrdering(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	var ids []int
+	for i, name := range []string{"charlie", "alpha", "bravo"} {
+		created := mustCreateContent(t, repo, conformanceContent(name, time.Duration(i)*time.Hour,
+			&model.Details{ContentTypeID: 1, Value: name + " 1"}, &model.Details{ContentTypeID: 1, Value: name + " 2"}))
+		ids = append(ids, created.ID)
+	}
+
+	all, err := repo.GetAllContent(ctx)
+	if err != nil {
+		t.Fatalf("GetAllContent() unexpected error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("GetAllContent() got = %d items, expected = 3", len(all))
+	}
+	for i, c := range all {
+		if c.ID != ids[i] {
+			t.Errorf("GetAllContent()[%d] ID = %d, expected = %d", i, c.ID, ids[i])
+		}
+		// Details must be grouped under their own content, in insertion order
+		if len(c.Details) != 2 || c.Details[0].Value != c.Name+" 1" || c.Details[1].Value != c.Name+" 2" ||
+			c.Details[0].ContentID != c.ID || c.Details[0].ID >= c.Details[1].ID {
+			t.Errorf("GetAllContent()[%d] details = %v, expected the two details of %s", i, printSlice(c.Details), c.Name)
+		}
+	}
+
+	listed, err := repo.ListContent(ctx, ContentFilter{Limit: 10, SortBy: SortByName})
+	if err != nil {
+		t.Fatalf("ListContent() unexpected error: %v", err)
+	}
+	if len(listed) != 3 || listed[0].Name != "alpha" || listed[1].Name != "bravo" || listed[2].Name != "charlie" {
+		t.Errorf("ListContent() got = %v, expected content ordered by name", printSlice(listed))
+	}
+
+	for i, id := range []int{ids[0], ids[2]} {
+		deletedAt := conformanceTimestamp.Add(time.Duration(i) * time.Minute)
+		if err := repo.SoftDeleteContent(ctx, id, deletedAt, nil); err != nil {
+			t.Fatalf("SoftDeleteContent() unexpected error: %v", err)
+		}
+	}
+	deleted, err := repo.GetDeletedContent(ctx)
+	if err != nil {
+		t.Fatalf("GetDeletedContent() unexpected error: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0].ID != ids[2] || deleted[1].ID != ids[0] {
+		t.Errorf("GetDeletedContent() got = %v, expected the most recently deleted first", printSlice(deleted))
+	}
+
+	update := conformanceContent("alpha updated", time.Hour, &model.Details{ContentTypeID: 1, Value: "new"})
+	update.ID = ids[1]
+	if _, err := repo.UpdateContentWithDetails(ctx, update, nil); err != nil {
+		t.Fatalf("UpdateContentWithDetails() unexpected error: %v", err)
+	}
+	revisions, err := repo.GetRevisions(ctx, ids[1])
+	if err != nil {
+		t.Fatalf("GetRevisions() unexpected error: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 1 || revisions[1].Snapshot.Name != "alpha updated" {
+		t.Errorf("GetRevisions() got = %+v, expected the oldest revision first", revisions)
+	}
+}
+
+func testConformanceListContent(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	ids := make(map[string]int)
+	for i, name := range []string{"charlie", "alpha", "bravo", "bravissimo", "deleted"} {
+		contentTypeID := 1
+		if i%2 == 1 {
+			contentTypeID = 2
+		}
+		created := mustCreateContent(t, repo, conformanceContent(name, time.Duration(i)*time.Hour,
+			&model.Details{ContentTypeID: contentTypeID, Value: "value"}))
+		ids[name] = created.ID
+	}
+	if err := repo.SoftDeleteContent(ctx, ids["deleted"], conformanceTimestamp, nil); err != nil {
+		t.Fatalf("SoftDeleteContent() unexpected error: %v", err)
+	}
+	if _, err := repo.UpdateContentStatus(ctx, ids["bravo"], model.StatusDraft, model.StatusReview,
+		conformanceTimestamp, "editor", nil); err != nil {
+		t.Fatalf("UpdateContentStatus() unexpected error: %v", err)
+	}
+
+	createdAfter := conformanceTimestamp
+	tests := []struct {
+		name     string
+		filter   ContentFilter
+		expected []string
+	}{
+		{
+			name:     "sorted by name",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName},
+			expected: []string{"alpha", "bravissimo", "bravo", "charlie"},
+		},
+		{
+			name:     "sorted by creation date descending",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByCreationDate, Descending: true},
+			expected: []string{"bravissimo", "bravo", "alpha", "charlie"},
+		},
+		{
+			name:     "limited",
+			filter:   ContentFilter{Limit: 2, SortBy: SortByName},
+			expected: []string{"alpha", "bravissimo"},
+		},
+		{
+			name: "after name cursor",
+			filter: ContentFilter{Limit: 10, SortBy: SortByName,
+				Cursor: &Cursor{SortBy: SortByName, Value: "alpha", ID: ids["alpha"]}},
+			expected: []string{"bravissimo", "bravo", "charlie"},
+		},
+		{
+			name: "after date cursor",
+			filter: ContentFilter{Limit: 10, SortBy: SortByCreationDate, Cursor: &Cursor{SortBy: SortByCreationDate,
+				Value: conformanceTimestamp.Add(time.Hour).Format(time.RFC3339Nano), ID: ids["alpha"]}},
+			expected: []string{"bravo", "bravissimo"},
+		},
+		{
+			name:     "name prefix",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, NamePrefix: "brav"},
+			expected: []string{"bravissimo", "bravo"},
+		},
+		{
+			name:     "content type",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, ContentType: "image"},
+			expected: []string{"alpha", "bravissimo"},
+		},
+		{
+			name:     "created after",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, CreatedAfter: &createdAfter},
+			expected: []string{"alpha", "bravissimo", "bravo"},
+		},
+		{
+			name:     "status",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, Status: model.StatusReview},
+			expected: []string{"bravo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := repo.ListContent(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListContent() unexpected error: %v", err)
+			}
+			names := make([]string, 0, len(content))
+			for _, c := range content {
+				names = append(names, c.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.expected) {
+				t.Errorf("ListContent() got = %v, expected = %v", names, tt.expected)
+			}
+		})
+	}
+
+	_, err := repo.ListContent(ctx, ContentFilter{Limit: 10, SortBy: SortByCreationDate,
+		Cursor: &Cursor{SortBy: SortByCreationDate, Value: "not a time", ID: 1}})
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ListContent() error = %v, expected = %v", err, ErrInvalidCursor)
+	}
+}
+
+func testConformanceScheduledTransitions(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	publishAt := conformanceTimestamp.Add(time.Hour)
+	unpublishAt := publishAt.Add(time.Hour)
+	scheduled := conformanceContent("scheduled", 0, &model.Details{ContentTypeID: 1, Value: "x"})
+	scheduled.Status = model.StatusReview
+	scheduled.PublishAt = &publishAt
+	scheduled.UnpublishAt = &unpublishAt
+	created := mustCreateContent(t, repo, scheduled)
+
+	transitioned, err := repo.ApplyScheduledTransitions(ctx, conformanceTimestamp, 10)
+	if err != nil || len(transitioned) != 0 {
+		t.Errorf("ApplyScheduledTransitions() got = %v, %v, expected nothing due", printSlice(transitioned), err)
+	}
+
+	transitioned, err = repo.ApplyScheduledTransitions(ctx, publishAt, 10)
+	if err != nil {
+		t.Fatalf("ApplyScheduledTransitions() unexpected error: %v", err)
+	}
+	if len(transitioned) != 1 || transitioned[0].ID != created.ID || transitioned[0].Status != model.StatusPublished ||
+		transitioned[0].PublishAt != nil || transitioned[0].LastModifiedBy != model.SchedulerAuthor ||
+		!transitioned[0].LastModifiedDate.Equal(publishAt) {
+		t.Errorf("ApplyScheduledTransitions() got = %v, expected the content published", printSlice(transitioned))
+	}
+
+	transitioned, err = repo.ApplyScheduledTransitions(ctx, publishAt.Add(2*time.Hour), 10)
+	if err != nil || len(transitioned) != 1 || transitioned[0].Status != model.StatusArchived ||
+		transitioned[0].UnpublishAt != nil {
+		t.Errorf("ApplyScheduledTransitions() got = %v, %v, expected the content archived",
+			printSlice(transitioned), err)
+	}
+
+	if revisions, err := repo.GetRevisions(ctx, created.ID); err != nil || len(revisions) != 3 {
+		t.Errorf("GetRevisions() got = %d revisions, %v, expected = 3", len(revisions), err)
+	}
+
+	// Content without details is transitioned and snapshotted like any other content
+	empty := conformanceContent("empty", 0)
+	empty.Status = model.StatusReview
+	emptyPublishAt := publishAt.Add(4 * time.Hour)
+	empty.PublishAt = &emptyPublishAt
+	createdEmpty := mustCreateContent(t, repo, empty)
+	before, err := repo.GetRevisions(ctx, createdEmpty.ID)
+	if err != nil {
+		t.Fatalf("GetRevisions() unexpected error: %v", err)
+	}
+
+	transitioned, err = repo.ApplyScheduledTransitions(ctx, emptyPublishAt, 10)
+	if err != nil || len(transitioned) != 1 || transitioned[0].ID != createdEmpty.ID ||
+		transitioned[0].Status != model.StatusPublished || len(transitioned[0].Details) != 0 {
+		t.Errorf("ApplyScheduledTransitions() got = %v, %v, expected the content without details published",
+			printSlice(transitioned), err)
+	}
+	after, err := repo.GetRevisions(ctx, createdEmpty.ID)
+	if err != nil || len(after) != len(before)+1 {
+		t.Fatalf("GetRevisions() got = %d revisions, %v, expected = %d", len(after), err, len(before)+1)
+	}
+	if snapshot := after[len(after)-1].Snapshot; snapshot == nil || snapshot.Status != model.StatusPublished ||
+		len(snapshot.Details) != 0 {
+		t.Errorf("GetRevisions() last snapshot = %+v, expected the published content without details", snapshot)
+	}
+}
+
+func testConformanceContentTypes(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	for id, name := range map[int]string{1: "text", 2: "image", 3: "video"} {
+		byName, err := repo.GetContentTypeByName(ctx, name)
+		if err != nil || byName == nil || byName.ID != id {
+			t.Errorf("GetContentTypeByName(%q) got = %v, %v, expected ID %d", name, byName, err, id)
+		}
+		byID, err := repo.GetContentTypeByID(ctx, id)
+		if err != nil || byID == nil || byID.Name != name {
+			t.Errorf("GetContentTypeByID(%d) got = %v, %v, expected %q", id, byID, err, name)
+		}
+	}
+
+	text, _ := repo.GetContentTypeByName(ctx, "text")
+	if text == nil || text.Rules.MaxLength != 10000 {
+		t.Errorf("GetContentTypeByName() got = %+v, expected the default text rules", text)
+	}
+
+	created, err := repo.CreateContentType(ctx, &model.ContentType{Name: "audio",
+		Rules: model.ValueRules{Format: model.FormatURL, MimeTypes: []string{"audio/*"}}})
+	if err != nil {
+		t.Fatalf("CreateContentType() unexpected error: %v", err)
+	}
+	got, err := repo.GetContentTypeByID(ctx, created.ID)
+	if err != nil || got == nil || got.Name != "audio" || len(got.Rules.MimeTypes) != 1 {
+		t.Errorf("GetContentTypeByID() got = %+v, %v, expected the created type", got, err)
+	}
+	if _, err := repo.CreateContentType(ctx, &model.ContentType{Name: "audio"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateContentType() error = %v, expected = %v", err, ErrDuplicate)
+	}
+
+	contentTypes, err := repo.GetContentTypes(ctx)
+	if err != nil {
+		t.Fatalf("GetContentTypes() unexpected error: %v", err)
+	}
+	var names []string
+	for _, ct := range contentTypes {
+		names = append(names, ct.Name)
+	}
+	if fmt.Sprint(names) != "[audio image text video]" {
+		t.Errorf("GetContentTypes() got = %v, expected the types ordered by name", names)
+	}
+
+	sound := mustCreateContent(t, repo, conformanceContent("sound", 0,
+		&model.Details{ContentTypeID: created.ID, Value: "x"}))
+	if err := repo.DeleteContentType(ctx, created.ID); !errors.Is(err, ErrInUse) {
+		t.Errorf("DeleteContentType() error = %v, expected = %v", err, ErrInUse)
+	}
+
+	// The revisions of purged content still reference the type
+	if err := repo.PurgeContent(ctx, sound.ID, nil); err != nil {
+		t.Fatalf("PurgeContent() unexpected error: %v", err)
+	}
+	if err := repo.DeleteContentType(ctx, created.ID); !errors.Is(err, ErrInUse) {
+		t.Errorf("DeleteContentType() after purge error = %v, expected = %v", err, ErrInUse)
+	}
+
+	unused, err := repo.CreateContentType(ctx, &model.ContentType{Name: "quote"})
+	if err != nil {
+		t.Fatalf("CreateContentType() unexpected error: %v", err)
+	}
+	if err := repo.DeleteContentType(ctx, unused.ID); err != nil {
+		t.Errorf("DeleteContentType() of an unused type unexpected error: %v", err)
+	}
+}
+
+func testConformanceDetails(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	empty := mustCreateContent(t, repo, conformanceContent("empty", 0))
+	ordered := mustCreateContent(t, repo, conformanceContent("ordered", time.Hour,
+		&model.Details{ContentTypeID: 1, Value: "first"}, &model.Details{ContentTypeID: 2, Value: "second"},
+		&model.Details{ContentTypeID: 1, Value: "third"}))
+
+	values := func(c *model.Content) []string {
+		result := make([]string, 0)
+		for i, d := range c.Details {
+			if d.Position != i {
+				t.Errorf("detail %q position got = %d, expected = %d", d.Value, d.Position, i)
+			}
+			result = append(result, d.Value)
+		}
+		return result
+	}
+
+	got, err := repo.GetContentByID(ctx, empty.ID)
+	if err != nil || got == nil || len(got.Details) != 0 {
+		t.Fatalf("GetContentByID() got = %+v, %v, expected content without details", got, err)
+	}
+	all, err := repo.GetAllContent(ctx)
+	if err != nil || len(all) != 2 || all[0].ID != empty.ID || len(all[0].Details) != 0 {
+		t.Errorf("GetAllContent() got = %v, %v, expected both content items", printSlice(all), err)
+	}
+	page, err := repo.ListContent(ctx, ContentFilter{Limit: 10})
+	if err != nil || len(page) != 2 || page[0].ID != empty.ID {
+		t.Errorf("ListContent() got = %v, %v, expected both content items", printSlice(page), err)
+	}
+	updated, err := repo.UpdateContentStatus(ctx, empty.ID, model.StatusDraft, model.StatusReview,
+		conformanceTimestamp, "editor", nil)
+	if err != nil || updated == nil || updated.Status != model.StatusReview {
+		t.Errorf("UpdateContentStatus() got = %+v, %v, expected the content without details", updated, err)
+	}
+
+	// Details are returned in the order in which they were written, whatever their IDs
+	got, err = repo.GetContentByID(ctx, ordered.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetContentByID() got = %v, %v, expected the created content", got, err)
+	}
+	if v := values(got); !reflect.DeepEqual(v, []string{"first", "second", "third"}) {
+		t.Errorf("GetContentByID() details got = %v, expected in creation order", v)
+	}
+	reordered := conformanceContent("ordered", time.Hour, got.Details[2], got.Details[0], got.Details[1])
+	reordered.ID = ordered.ID
+	if _, err := repo.UpdateContentWithDetails(ctx, reordered, nil); err != nil {
+		t.Fatalf("UpdateContentWithDetails() unexpected error: %v", err)
+	}
+	got, err = repo.GetContentByID(ctx, ordered.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetContentByID() got = %v, %v, expected the updated content", got, err)
+	}
+	if v := values(got); !reflect.DeepEqual(v, []string{"third", "first", "second"}) {
+		t.Errorf("GetContentByID() details got = %v, expected in the updated order", v)
+	}
+
+	// Removing all details keeps the content
+	reordered.Details = nil
+	if _, err := repo.UpdateContentWithDetails(ctx, reordered, nil); err != nil {
+		t.Fatalf("UpdateContentWithDetails() unexpected error: %v", err)
+	}
+	if err := repo.SoftDeleteContent(ctx, ordered.ID, conformanceTimestamp, nil); err != nil {
+		t.Fatalf("SoftDeleteContent() unexpected error: %v", err)
+	}
+	deleted, err := repo.GetDeletedContent(ctx)
+	if err != nil || len(deleted) != 1 || deleted[0].ID != ordered.ID || len(deleted[0].Details) != 0 {
+		t.Errorf("GetDeletedContent() got = %v, %v, expected the content without details", printSlice(deleted), err)
+	}
+}
+
+func testConformanceNotFound(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	const missing = 999999
+
+	if got, err := repo.GetContentByID(ctx, missing); got != nil || err != nil {
+		t.Errorf("GetContentByID() got = %v, %v, expected = nil, nil", got, err)
+	}
+	if got, err := repo.GetRevision(ctx, missing, 1); got != nil || err != nil {
+		t.Errorf("GetRevision() got = %v, %v, expected = nil, nil", got, err)
+	}
+	if got, err := repo.GetRevisions(ctx, missing); len(got) != 0 || err != nil {
+		t.Errorf("GetRevisions() got = %v, %v, expected no revisions", got, err)
+	}
+	if got, err := repo.GetContentTypeByName(ctx, "missing"); got != nil || err != nil {
+		t.Errorf("GetContentTypeByName() got = %v, %v, expected = nil, nil", got, err)
+	}
+	if got, err := repo.GetContentTypeByID(ctx, missing); got != nil || err != nil {
+		t.Errorf("GetContentTypeByID() got = %v, %v, expected = nil, nil", got, err)
+	}
+
+	update := conformanceContent("missing", 0, &model.Details{ContentTypeID: 1, Value: "x"})
+	update.ID = missing
+	if _, err := repo.UpdateContentWithDetails(ctx, update, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentWithDetails() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if _, err := repo.UpdateContentStatus(ctx, missing, model.StatusDraft, model.StatusReview,
+		conformanceTimestamp, "editor", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentStatus() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.SoftDeleteContent(ctx, missing, conformanceTimestamp, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SoftDeleteContent() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.RestoreContent(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RestoreContent() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.PurgeContent(ctx, missing, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PurgeContent() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.UpdateContentType(ctx, &model.ContentType{ID: missing, Name: "missing"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentType() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.DeleteContentType(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteContentType() error = %v, expected = %v", err, ErrNotFound)
+	}
+
+	// Soft-deleted content is invisible to reads and to writes other than restore and purge
+	deleted := mustCreateContent(t, repo, conformanceContent("deleted", 0, &model.Details{ContentTypeID: 1, Value: "x"}))
+	if err := repo.SoftDeleteContent(ctx, deleted.ID, conformanceTimestamp, nil); err != nil {
+		t.Fatalf("SoftDeleteContent() unexpected error: %v", err)
+	}
+	if got, err := repo.GetContentByID(ctx, deleted.ID); got != nil || err != nil {
+		t.Errorf("GetContentByID() got = %v, %v, expected = nil, nil for deleted content", got, err)
+	}
+	update.ID = deleted.ID
+	if _, err := repo.UpdateContentWithDetails(ctx, update, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentWithDetails() error = %v, expected = %v for deleted content", err, ErrNotFound)
+	}
+	if _, err := repo.UpdateContentStatus(ctx, deleted.ID, model.StatusDraft, model.StatusReview,
+		conformanceTimestamp, "editor", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentStatus() error = %v, expected = %v for deleted content", err, ErrNotFound)
+	}
+	if err := repo.SoftDeleteContent(ctx, deleted.ID, conformanceTimestamp, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SoftDeleteContent() error = %v, expected = %v for deleted content", err, ErrNotFound)
+	}
+	revisions, err := repo.GetRevisions(ctx, deleted.ID)
+	if err != nil || len(revisions) == 0 {
+		t.Fatalf("GetRevisions() got = %d revisions, %v, expected the history of the deleted content", len(revisions), err)
+	}
+	if err := repo.PurgeContent(ctx, deleted.ID, nil); err != nil {
+		t.Errorf("PurgeContent() unexpected error: %v", err)
+	}
+
+	// Purged content is gone, but its revision history is kept
+	if trash, err := repo.GetDeletedContent(ctx); err != nil || slices.ContainsFunc(trash, func(c *model.Content) bool {
+		return c.ID == deleted.ID
+	}) {
+		t.Errorf("GetDeletedContent() got = %v, %v, expected no purged content", printSlice(trash), err)
+	}
+	if got, err := repo.GetRevisions(ctx, deleted.ID); err != nil || len(got) != len(revisions) {
+		t.Errorf("GetRevisions() got = %d revisions, %v, expected = %d after purge", len(got), err, len(revisions))
+	}
+	if next := mustCreateContent(t, repo, conformanceContent("next", 0)); next.ID == deleted.ID {
+		t.Errorf("CreateContentWithDetails() reused the ID %d of purged content", deleted.ID)
+	}
+}
+
+func testConformanceConditionalWrites(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	created := mustCreateContent(t, repo, conformanceContent("first", 0, &model.Details{ContentTypeID: 1, Value: "x"}))
+	read, err := repo.GetContentByID(ctx, created.ID)
+	if err != nil || read == nil {
+		t.Fatalf("GetContentByID() got = %v, %v, expected the content", read, err)
+	}
+	version := read.LastModifiedDate
+	stale := version.Add(-time.Second)
+
+	update := conformanceContent("updated", time.Hour, &model.Details{ContentTypeID: 1, Value: "y"})
+	update.ID = created.ID
+	if _, err := repo.UpdateContentWithDetails(ctx, update, &stale); !errors.Is(err, ErrModified) {
+		t.Errorf("UpdateContentWithDetails() error = %v, expected = %v", err, ErrModified)
+	}
+	if got, _ := repo.GetContentByID(ctx, created.ID); got == nil || got.Name != "first" {
+		t.Errorf("GetContentByID() got = %v, expected the unchanged content after a failed condition", got)
+	}
+	if revisions, _ := repo.GetRevisions(ctx, created.ID); len(revisions) != 1 {
+		t.Errorf("GetRevisions() got %d revisions, expected = 1 after a failed condition", len(revisions))
+	}
+
+	// A write matching the version succeeds and changes it, making the version read before stale
+	if _, err := repo.UpdateContentWithDetails(ctx, update, &version); err != nil {
+		t.Fatalf("UpdateContentWithDetails() unexpected error: %v", err)
+	}
+	if _, err := repo.UpdateContentStatus(ctx, created.ID, model.StatusDraft, model.StatusReview, conformanceTimestamp,
+		"editor", &version); !errors.Is(err, ErrModified) {
+		t.Errorf("UpdateContentStatus() error = %v, expected = %v", err, ErrModified)
+	}
+	if err := repo.SoftDeleteContent(ctx, created.ID, conformanceTimestamp, &version); !errors.Is(err, ErrModified) {
+		t.Errorf("SoftDeleteContent() error = %v, expected = %v", err, ErrModified)
+	}
+	if err := repo.PurgeContent(ctx, created.ID, &version); !errors.Is(err, ErrModified) {
+		t.Errorf("PurgeContent() error = %v, expected = %v", err, ErrModified)
+	}
+
+	current, err := repo.GetContentByID(ctx, created.ID)
+	if err != nil || current == nil || current.Name != "updated" {
+		t.Fatalf("GetContentByID() got = %v, %v, expected the updated content", current, err)
+	}
+	version = current.LastModifiedDate
+	transitioned, err := repo.UpdateContentStatus(ctx, created.ID, model.StatusDraft, model.StatusReview,
+		conformanceTimestamp.Add(2*time.Hour), "editor", &version)
+	if err != nil || transitioned.Status != model.StatusReview {
+		t.Fatalf("UpdateContentStatus() got = %v, %v, expected the content in review", transitioned, err)
+	}
+	version = transitioned.LastModifiedDate
+	if err := repo.SoftDeleteContent(ctx, created.ID, conformanceTimestamp, &version); err != nil {
+		t.Errorf("SoftDeleteContent() unexpected error: %v", err)
+	}
+
+	// Conditions never turn missing content into ErrModified
+	update.ID = 999999
+	if _, err := repo.UpdateContentWithDetails(ctx, update, &version); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentWithDetails() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.SoftDeleteContent(ctx, created.ID, conformanceTimestamp, &version); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SoftDeleteContent() error = %v, expected = %v for deleted content", err, ErrNotFound)
+	}
+}
+
+func testConformanceConcurrency(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	const workers = 10
+
+	var wg sync.WaitGroup
+	ids := make([]int, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			created, err := repo.CreateContentWithDetails(ctx, conformanceContent(fmt.Sprintf("content %d", i), 0,
+				&model.Details{ContentTypeID: 1, Value: "x"}))
+			if err != nil {
+				t.Errorf("CreateContentWithDetails() unexpected error: %v", err)
+				return
+			}
+			ids[i] = created.ID
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			t.Fatalf("CreateContentWithDetails() IDs = %v, expected unique IDs", ids)
+		}
+		seen[id] = true
+	}
+
+	// Concurrent transitions from the same status must let exactly one writer win
+	var mu sync.Mutex
+	succeeded := 0
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.UpdateContentStatus(ctx, ids[0], model.StatusDraft, model.StatusReview,
+				conformanceTimestamp, "editor", nil)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				t.Errorf("UpdateContentStatus() unexpected error: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("UpdateContentStatus() succeeded %d times, expected = 1", succeeded)
+	}
+	if revisions, err := repo.GetRevisions(ctx, ids[0]); err != nil || len(revisions) != 2 {
+		t.Errorf("GetRevisions() got = %d revisions, %v, expected = 2", len(revisions), err)
+	}
+}
+
+func printSlice[T any](items []*T) string {
+	var builder strings.Builder
+	for _, item := range items {
+		builder.WriteString(fmt.Sprintf("%+v\n", *item))
+	}
+	return builder.String()
+}
+
+func testConformanceSubMicrosecondTimes(t *testing.T, repo ContentRepository) {
+	ctx := context.Background()
+	// A clock with nanoseconds in a zone other than UTC, finer than the microseconds databases store
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	clock := conformanceTimestamp.Add(123456789 * time.Nanosecond).In(zone)
+	now := func() time.Time {
+		clock = clock.Add(time.Second + 987654321*time.Nanosecond)
+		return clock
+	}
+
+	// The version returned by each write is the one stored, so it can be sent back as the condition of the next write
+	content := conformanceContent("first", 0, &model.Details{ContentTypeID: 1, Value: "x"})
+	content.CreationDate, content.LastModifiedDate = now(), clock
+	created := mustCreateContent(t, repo, content)
+	version := created.LastModifiedDate
+	assertStoredVersion := func(method string) {
+		t.Helper()
+		read, err := repo.GetContentByID(ctx, created.ID)
+		if err != nil || read == nil || !read.LastModifiedDate.Equal(version) {
+			t.Fatalf("%s() returned last_modified_date = %v, stored = %v, %v", method, version, read, err)
+		}
+	}
+	assertStoredVersion("CreateContentWithDetails")
+
+	for _, name := range []string{"second", "third"} {
+		update := conformanceContent(name, 0, &model.Details{ContentTypeID: 1, Value: name})
+		update.ID, update.CreationDate, update.LastModifiedDate = created.ID, created.CreationDate, now()
+		updated, err := repo.UpdateContentWithDetails(ctx, update, &version)
+		if err != nil {
+			t.Fatalf("UpdateContentWithDetails() with the returned version unexpected error: %v", err)
+		}
+		version = updated.LastModifiedDate
+		assertStoredVersion("UpdateContentWithDetails")
+	}
+
+	transitioned, err := repo.UpdateContentStatus(ctx, created.ID, model.StatusDraft, model.StatusReview, now(),
+		"editor", &version)
+	if err != nil {
+		t.Fatalf("UpdateContentStatus() with the returned version unexpected error: %v", err)
+	}
+	version = transitioned.LastModifiedDate
+	assertStoredVersion("UpdateContentStatus")
+
+	if err := repo.SoftDeleteContent(ctx, created.ID, now(), &version); err != nil {
+		t.Errorf("SoftDeleteContent() with the returned version unexpected error: %v", err)
+	}
+}
+
+func testConformanceSearchSnippets(t *testing.T, repo ContentRepository) {
+	content := conformanceContent("Gopher <script>alert(1)</script>", 0,
+		&model.Details{ContentTypeID: 1, Value: `<img src=x onerror="alert(2)"> gopher & friends`})
+	mustCreateContent(t, repo, content)
+
+	results, err := repo.SearchContent(context.Background(), "gopher", 10, "")
+	if err != nil || len(results) != 1 {
+		t.Fatalf("SearchContent() got = %d results, %v, expected = 1", len(results), err)
+	}
+
+	// Snippets are HTML, so only the <mark> tags of the matches may be markup
+	snippet := results[0].Snippet
+	if !strings.Contains(snippet, "<mark>") || !strings.Contains(snippet, "&lt;script&gt;") {
+		t.Errorf("SearchContent() snippet = %q, expected highlighted matches and escaped markup", snippet)
+	}
+	unmarked := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(snippet)
+	if strings.ContainsAny(unmarked, `<>"`) {
+		t.Errorf("SearchContent() snippet = %q, expected no markup other than <mark> tags", snippet)
+	}
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// uniqueViolation is the Postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// GetContentTypes returns all content types ordered by name
+func (r *PostgresContentRepository) GetContentTypes(ctx context.Context) ([]*model.ContentType, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn.DB.QueryContext(ctx, "SELECT id, name, rules FROM content_type ORDER BY name")
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to fetch content types", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	contentTypes := make([]*model.ContentType, 0)
+	for rows.Next() {
+		contentType, err := scanContentType(ctx, rows)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to scan content type", "error", err)
+			return nil, err
+		}
+		contentTypes = append(contentTypes, contentType)
+	}
+	if err := rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("failed to iterate content types", "error", err)
+		return nil, err
+	}
+
+	return contentTypes, nil
+}
+
+// CreateContentType inserts a new content type, returning ErrDuplicate if the name is already taken
+func (r *PostgresContentRepository) CreateContentType(
+	ctx context.Context, contentType *model.ContentType) (*model.ContentType, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rules, err := json.Marshal(contentType.Rules)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to marshal content type rules", "error", err)
+		return nil, err
+	}
+
+	err = r.conn.DB.QueryRowContext(ctx, "INSERT INTO content_type (name, rules) VALUES ($1, $2) RETURNING id",
+		contentType.Name, string(rules)).Scan(&contentType.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+		logging.FromContext(ctx).Error("failed to insert content type", "error", err)
+		return nil, err
+	}
+
+	return contentType, nil
+}
+
+// UpdateContentType replaces the name and rules of a content type, returning ErrDuplicate if the name is already taken
+func (r *PostgresContentRepository) UpdateContentType(ctx context.Context, contentType *model.ContentType) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rules, err := json.Marshal(contentType.Rules)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to marshal content type rules", "error", err)
+		return err
+	}
+
+	err = r.execAffectingOne(ctx, "UPDATE content_type SET name = $1, rules = $2 WHERE id = $3",
+		contentType.Name, string(rules), contentType.ID)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// DeleteContentType removes a content type, returning ErrInUse if content details or revisions still reference it.
+// The type row is locked first, which blocks details from being inserted against it until the delete completes.
+func (r *PostgresContentRepository) DeleteContentType(ctx context.Context, id int) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// Revisions are checked too, so that any past revision can still be restored. Snapshots are queried by the json
+	// tags of model.Content and model.Details.
+	inUseQuery := `
+		SELECT EXISTS (SELECT 1 FROM content_details WHERE content_type_id = $1)
+		    OR EXISTS (SELECT 1 FROM content_revision
+		               WHERE snapshot -> 'Details' @> jsonb_build_array(jsonb_build_object('ContentTypeID', $1::int)))`
+
+	return deleteContentType(ctx, r.conn.DB, "SELECT id FROM content_type WHERE id = $1 FOR UPDATE", inUseQuery, id)
+}
+
+// deleteContentType deletes a content type in a transaction. lockQuery selects and locks the type row and
+// inUseQuery reports whether anything references the type; both take the type ID as their only argument.
+func deleteContentType(ctx context.Context, db *sql.DB, lockQuery, inUseQuery string, id int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to start the transaction", "error", err)
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			err := tx.Rollback()
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to roll back transaction", "error", err)
+			}
+		}
+	}()
+
+	err = tx.QueryRowContext(ctx, lockQuery, id).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrNotFound
+			return err
+		}
+		logging.FromContext(ctx).Error("failed to lock content type", "error", err)
+		return err
+	}
+
+	var inUse bool
+	err = tx.QueryRowContext(ctx, inUseQuery, id).Scan(&inUse)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to check content type references", "error", err)
+		return err
+	}
+	if inUse {
+		err = ErrInUse
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM content_type WHERE id = $1", id)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to delete content type", "error", err)
+		return err
+	}
+
+	// commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to commit the transaction", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// scanContentType scans a content_type row and decodes its value rules
+func scanContentType(ctx context.Context, row interface{ Scan(dest ...any) error }) (*model.ContentType, error) {
+	var contentType model.ContentType
+	var rules []byte
+	if err := row.Scan(&contentType.ID, &contentType.Name, &rules); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rules, &contentType.Rules); err != nil {
+		logging.FromContext(ctx).Error("failed to unmarshal content type rules", "error", err)
+		return nil, err
+	}
+
+	return &contentType, nil
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	var sqliteErr *sqlite.Error
+	return (errors.As(err, &pqErr) && pqErr.Code == uniqueViolation) ||
+		(errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/model"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type SortField string
+
+const (
+	SortByCreationDate     SortField = "creation_date"
+	SortByLastModifiedDate SortField = "last_modified_date"
+	SortByName             SortField = "name"
+)
+
+// Valid reports whether the sort field is supported
+func (f SortField) Valid() bool {
+	switch f {
+	case SortByCreationDate, SortByLastModifiedDate, SortByName:
+		return true
+	}
+	return false
+}
+
+// ContentFilter describes a single page of content to list
+type ContentFilter struct {
+	Limit         int
+	SortBy        SortField
+	Descending    bool
+	Cursor        *Cursor             // Position after which the page starts, nil for the first page
+	Status        model.ContentStatus // Only list content in this workflow status, empty for any status
+	NamePrefix    string
+	ContentType   string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// Cursor is a keyset pagination position: the sort value and ID of the last item on the previous page.
+// The sort settings are included so a cursor cannot be reused with a different ordering.
+type Cursor struct {
+	SortBy     SortField `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	Value      string    `json:"v"`
+	ID         int       `json:"i"`
+}
+
+// EncodeCursor encodes a cursor into an opaque URL-safe string
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c) // Cursor only holds marshalable fields
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor. The sort value must be valid for the sort field of the
+// cursor, so a decoded cursor can be used with any repository listing in that order.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(b, &c); err != nil || !c.SortBy.Valid() || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	if _, err = c.sortValue(c.SortBy); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// sortValue decodes the sort value of the cursor: a time for the date sort fields and the name otherwise
+func (c *Cursor) sortValue(sortBy SortField) (any, error) {
+	if sortBy == SortByName {
+		return c.Value, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"github.com/g-stro/content-management-service/internal/model"
+	"time"
+)
+
+// InstrumentedContentRepository records the duration and outcome of every call to the repository it wraps
+type InstrumentedContentRepository struct {
+	repo     ContentRepository
+	duration *metrics.Histogram
+}
+
+// NewInstrumentedContentRepository wraps repo, observing the duration of its calls in seconds in a histogram
+// labelled by method and outcome
+func NewInstrumentedContentRepository(
+	repo ContentRepository, duration *metrics.Histogram) *InstrumentedContentRepository {
+	return &InstrumentedContentRepository{repo: repo, duration: duration}
+}
+
+// NewQueryDurationHistogram registers the histogram used by NewInstrumentedContentRepository
+func NewQueryDurationHistogram(reg *metrics.Registry) *metrics.Histogram {
+	return reg.NewHistogram("cms_repository_query_duration_seconds",
+		"Duration of repository operations in seconds, including all of their queries.",
+		metrics.DefaultBuckets, "method", "outcome")
+}
+
+// observe records a call that started at start once it has returned err. Missing records and failed conditional
+// writes are reported separately from failures.
+func (r *InstrumentedContentRepository) observe(method string, start time.Time, err *error) {
+	outcome := "ok"
+	switch {
+	case errors.Is(*err, ErrNotFound):
+		outcome = "not_found"
+	case errors.Is(*err, ErrModified):
+		outcome = "modified"
+	case *err != nil:
+		outcome = "error"
+	}
+	r.duration.Observe(time.Since(start).Seconds(), method, outcome)
+}
+
+func (r *InstrumentedContentRepository) GetAllContent(ctx context.Context) (_ []*model.Content, err error) {
+	defer r.observe("GetAllContent", time.Now(), &err)
+	return r.repo.GetAllContent(ctx)
+}
+
+func (r *InstrumentedContentRepository) ListContent(
+	ctx context.Context, filter ContentFilter) (_ []*model.Content, err error) {
+	defer r.observe("ListContent", time.Now(), &err)
+	return r.repo.ListContent(ctx, filter)
+}
+
+func (r *InstrumentedContentRepository) GetContentByID(ctx context.Context, id int) (_ *model.Content, err error) {
+	defer r.observe("GetContentByID", time.Now(), &err)
+	return r.repo.GetContentByID(ctx, id)
+}
+
+func (r *InstrumentedContentRepository) GetDeletedContent(ctx context.Context) (_ []*model.Content, err error) {
+	defer r.observe("GetDeletedContent", time.Now(), &err)
+	return r.repo.GetDeletedContent(ctx)
+}
+
+func (r *InstrumentedContentRepository) SearchContent(
+	ctx context.Context, query string, limit int, status model.ContentStatus) (_ []*model.SearchResult, err error) {
+	defer r.observe("SearchContent", time.Now(), &err)
+	return r.repo.SearchContent(ctx, query, limit, status)
+}
+
+func (r *InstrumentedContentRepository) CreateContentWithDetails(
+	ctx context.Context, content *model.Content) (_ *model.Content, err error) {
+	defer r.observe("CreateContentWithDetails", time.Now(), &err)
+	return r.repo.CreateContentWithDetails(ctx, content)
+}
+
+func (r *InstrumentedContentRepository) UpdateContentWithDetails(
+	ctx context.Context, content *model.Content, expectedModified *time.Time) (_ *model.Content, err error) {
+	defer r.observe("UpdateContentWithDetails", time.Now(), &err)
+	return r.repo.UpdateContentWithDetails(ctx, content, expectedModified)
+}
+
+func (r *InstrumentedContentRepository) SoftDeleteContent(
+	ctx context.Context, id int, deletedAt time.Time, expectedModified *time.Time) (err error) {
+	defer r.observe("SoftDeleteContent", time.Now(), &err)
+	return r.repo.SoftDeleteContent(ctx, id, deletedAt, expectedModified)
+}
+
+func (r *InstrumentedContentRepository) RestoreContent(ctx context.Context, id int) (err error) {
+	defer r.observe("RestoreContent", time.Now(), &err)
+	return r.repo.RestoreContent(ctx, id)
+}
+
+func (r *InstrumentedContentRepository) UpdateContentStatus(
+	ctx context.Context, id int, from, to model.ContentStatus, modifiedAt time.Time,
+	author string, expectedModified *time.Time) (_ *model.Content, err error) {
+	defer r.observe("UpdateContentStatus", time.Now(), &err)
+	return r.repo.UpdateContentStatus(ctx, id, from, to, modifiedAt, author, expectedModified)
+}
+
+func (r *InstrumentedContentRepository) PurgeContent(
+	ctx context.Context, id int, expectedModified *time.Time) (err error) {
+	defer r.observe("PurgeContent", time.Now(), &err)
+	return r.repo.PurgeContent(ctx, id, expectedModified)
+}
+
+func (r *InstrumentedContentRepository) ApplyScheduledTransitions(
+	ctx context.Context, now time.Time, limit int) (_ []*model.Content, err error) {
+	defer r.observe("ApplyScheduledTransitions", time.Now(), &err)
+	return r.repo.ApplyScheduledTransitions(ctx, now, limit)
+}
+
+func (r *InstrumentedContentRepository) GetRevisions(
+	ctx context.Context, contentID int) (_ []*model.Revision, err error) {
+	defer r.observe("GetRevisions", time.Now(), &err)
+	return r.repo.GetRevisions(ctx, contentID)
+}
+
+func (r *InstrumentedContentRepository) GetRevision(
+	ctx context.Context, contentID, revision int) (_ *model.Revision, err error) {
+	defer r.observe("GetRevision", time.Now(), &err)
+	return r.repo.GetRevision(ctx, contentID, revision)
+}
+
+func (r *InstrumentedContentRepository) GetContentTypeByName(
+	ctx context.Context, name string) (_ *model.ContentType, err error) {
+	defer r.observe("GetContentTypeByName", time.Now(), &err)
+	return r.repo.GetContentTypeByName(ctx, name)
+}
+
+func (r *InstrumentedContentRepository) GetContentTypeByID(
+	ctx context.Context, id int) (_ *model.ContentType, err error) {
+	defer r.observe("GetContentTypeByID", time.Now(), &err)
+	return r.repo.GetContentTypeByID(ctx, id)
+}
+
+func (r *InstrumentedContentRepository) GetContentTypes(ctx context.Context) (_ []*model.ContentType, err error) {
+	defer r.observe("GetContentTypes", time.Now(), &err)
+	return r.repo.GetContentTypes(ctx)
+}
+
+func (r *InstrumentedContentRepository) CreateContentType(
+	ctx context.Context, contentType *model.ContentType) (_ *model.ContentType, err error) {
+	defer r.observe("CreateContentType", time.Now(), &err)
+	return r.repo.CreateContentType(ctx, contentType)
+}
+
+func (r *InstrumentedContentRepository) UpdateContentType(
+	ctx context.Context, contentType *model.ContentType) (err error) {
+	defer r.observe("UpdateContentType", time.Now(), &err)
+	return r.repo.UpdateContentType(ctx, contentType)
+}
+
+func (r *InstrumentedContentRepository) DeleteContentType(ctx context.Context, id int) (err error) {
+	defer r.observe("DeleteContentType", time.Now(), &err)
+	return r.repo.DeleteContentType(ctx, id)
+}
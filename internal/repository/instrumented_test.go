@@ -0,0 +1,44 @@
+//go:build !integration
+
+package repository
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstrumentedContentRepository(t *testing.T) {
+	ctx := context.Background()
+	reg := metrics.NewRegistry()
+	repo := NewInstrumentedContentRepository(NewInMemoryContentRepository(), NewQueryDurationHistogram(reg))
+
+	if _, err := repo.CreateContentWithDetails(ctx, newTestContent("first", 0, 1, "hello")); err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+	if _, err := repo.GetContentByID(ctx, 1); err != nil {
+		t.Fatalf("GetContentByID() unexpected error: %v", err)
+	}
+	if _, err := repo.GetContentByID(ctx, 2); err != nil {
+		t.Fatalf("GetContentByID() unexpected error: %v", err)
+	}
+	if err := repo.SoftDeleteContent(ctx, 2, time.Now(), nil); err == nil {
+		t.Fatal("SoftDeleteContent() expected an error")
+	}
+
+	var b strings.Builder
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	for _, line := range []string{
+		`cms_repository_query_duration_seconds_count{method="CreateContentWithDetails",outcome="ok"} 1`,
+		`cms_repository_query_duration_seconds_count{method="GetContentByID",outcome="ok"} 2`,
+		`cms_repository_query_duration_seconds_count{method="SoftDeleteContent",outcome="not_found"} 1`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("WriteTo() got = \n%s\nexpected to contain %s", b.String(), line)
+		}
+	}
+}
@@ -0,0 +1,580 @@
+package repository
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/model"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultContentTypes are the content types every new in-memory repository starts with, matching the types seeded
+// by the initial database migration
+var defaultContentTypes = []model.ContentType{
+	{ID: 1, Name: "text", Rules: model.ValueRules{MaxLength: 10000}},
+	{ID: 2, Name: "image", Rules: model.ValueRules{Format: model.FormatURL, MimeTypes: []string{"image/*"}}},
+	{ID: 3, Name: "video", Rules: model.ValueRules{Format: model.FormatURL, MimeTypes: []string{"video/*"}}},
+}
+
+// InMemoryContentRepository is a ContentRepository that keeps all data in process memory. It is safe for concurrent
+// use and behaves like PostgresContentRepository, which makes it suitable for local development, demos and tests.
+// All data is lost when the process exits.
+type InMemoryContentRepository struct {
+	mu           sync.RWMutex
+	content      map[int]*model.Content
+	revisions    map[int][]*model.Revision
+	contentTypes map[int]*model.ContentType
+
+	lastContentID     int
+	lastDetailID      int
+	lastRevisionID    int
+	lastContentTypeID int
+}
+
+func NewInMemoryContentRepository() *InMemoryContentRepository {
+	r := &InMemoryContentRepository{
+		content:      make(map[int]*model.Content),
+		revisions:    make(map[int][]*model.Revision),
+		contentTypes: make(map[int]*model.ContentType),
+	}
+	for _, ct := range defaultContentTypes {
+		r.contentTypes[ct.ID] = cloneContentType(&ct)
+		r.lastContentTypeID = max(r.lastContentTypeID, ct.ID)
+	}
+	return r
+}
+
+func (r *InMemoryContentRepository) GetAllContent(ctx context.Context) ([]*model.Content, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.collect(func(c *model.Content) bool { return c.DeletedAt == nil }, compareByID), nil
+}
+
+// ListContent returns a single page of content matching the filter, using keyset pagination
+func (r *InMemoryContentRepository) ListContent(ctx context.Context, filter ContentFilter) ([]*model.Content, error) {
+	sortBy := filter.SortBy
+	if !sortBy.Valid() {
+		sortBy = SortByCreationDate
+	}
+
+	compare := func(a, b *model.Content) int {
+		c := compareSortField(sortBy, a, b)
+		if c == 0 {
+			c = a.ID - b.ID
+		}
+		if filter.Descending {
+			return -c
+		}
+		return c
+	}
+
+	var after *model.Content
+	if filter.Cursor != nil {
+		var err error
+		if after, err = cursorContent(sortBy, filter.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	content := r.collect(func(c *model.Content) bool {
+		switch {
+		case c.DeletedAt != nil:
+			return false
+		case filter.Status != "" && c.Status != filter.Status:
+			return false
+		case filter.NamePrefix != "" && !strings.HasPrefix(c.Name, filter.NamePrefix):
+			return false
+		case filter.ContentType != "" && !r.hasContentType(c, filter.ContentType):
+			return false
+		case filter.CreatedAfter != nil && !c.CreationDate.After(*filter.CreatedAfter):
+			return false
+		case filter.CreatedBefore != nil && !c.CreationDate.Before(*filter.CreatedBefore):
+			return false
+		case after != nil && compare(c, after) <= 0:
+			return false
+		}
+		return true
+	}, compare)
+
+	if filter.Limit >= 0 && len(content) > filter.Limit {
+		content = content[:filter.Limit]
+	}
+	return content, nil
+}
+
+func (r *InMemoryContentRepository) GetContentByID(ctx context.Context, id int) (*model.Content, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.content[id]
+	if !ok || c.DeletedAt != nil {
+		// A nil content with a nil error signals that no content exists for the ID
+		return nil, nil
+	}
+	return r.readContent(c), nil
+}
+
+// GetDeletedContent returns all soft-deleted content, most recently deleted first
+func (r *InMemoryContentRepository) GetDeletedContent(ctx context.Context) ([]*model.Content, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.collect(func(c *model.Content) bool { return c.DeletedAt != nil }, func(a, b *model.Content) int {
+		if c := b.DeletedAt.Compare(*a.DeletedAt); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	}), nil
+}
+
+// SearchContent performs a simple full-text search over content names, descriptions and text details, see rankContent
+func (r *InMemoryContentRepository) SearchContent(
+	ctx context.Context, query string, limit int, status model.ContentStatus) ([]*model.SearchResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	candidates := r.collect(func(c *model.Content) bool {
+		return c.DeletedAt == nil && (status == "" || c.Status == status)
+	}, compareByID)
+	return rankContent(candidates, r.textDetails, query, limit), nil
+}
+
+func (r *InMemoryContentRepository) CreateContentWithDetails(
+	ctx context.Context, content *model.Content) (*model.Content, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if content.Status == "" {
+		content.Status = model.StatusDraft // New content starts its workflow as a draft
+	}
+
+	r.lastContentID++
+	content.ID = r.lastContentID // Set the content ID after creation.
+	content.CreationDate = normalizeTime(content.CreationDate)
+	content.LastModifiedDate = normalizeTime(content.LastModifiedDate)
+	r.assignDetailIDs(content)
+
+	r.content[content.ID] = cloneContent(content)
+	r.insertRevision(content)
+
+	return content, nil
+}
+
+// UpdateContentWithDetails replaces the content fields and all of its details.
+// The workflow status is left unchanged, see UpdateContentStatus.
+func (r *InMemoryContentRepository) UpdateContentWithDetails(
+	ctx context.Context, content *model.Content, expectedModified *time.Time) (*model.Content, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.content[content.ID]
+	if !ok || existing.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	if !matchesModified(existing, expectedModified) {
+		return nil, ErrModified
+	}
+
+	content.CreationDate = existing.CreationDate
+	content.Status = existing.Status
+	content.LastModifiedDate = normalizeTime(content.LastModifiedDate)
+	content.DeletedAt = nil
+	r.assignDetailIDs(content)
+
+	r.content[content.ID] = cloneContent(content)
+	r.insertRevision(content)
+
+	return content, nil
+}
+
+// SoftDeleteContent marks content as deleted without removing it, so it can later be restored
+func (r *InMemoryContentRepository) SoftDeleteContent(
+	ctx context.Context, id int, deletedAt time.Time, expectedModified *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.content[id]
+	if !ok || c.DeletedAt != nil {
+		return ErrNotFound
+	}
+	if !matchesModified(c, expectedModified) {
+		return ErrModified
+	}
+	deletedAt = normalizeTime(deletedAt)
+	c.DeletedAt = &deletedAt
+	return nil
+}
+
+// RestoreContent clears the deletion mark of soft-deleted content
+func (r *InMemoryContentRepository) RestoreContent(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.content[id]
+	if !ok || c.DeletedAt == nil {
+		return ErrNotFound
+	}
+	c.DeletedAt = nil
+	return nil
+}
+
+// UpdateContentStatus moves content from one workflow status to another. The update only applies while the
+// content is still in the expected status, otherwise ErrNotFound is returned.
+func (r *InMemoryContentRepository) UpdateContentStatus(ctx context.Context, id int, from, to model.ContentStatus,
+	modifiedAt time.Time, author string, expectedModified *time.Time) (*model.Content, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.content[id]
+	if !ok || c.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	if !matchesModified(c, expectedModified) {
+		return nil, ErrModified
+	}
+	if c.Status != from {
+		return nil, ErrNotFound
+	}
+
+	c.Status = to
+	c.LastModifiedDate = normalizeTime(modifiedAt)
+	c.LastModifiedBy = author
+	r.insertRevision(c)
+
+	return r.readContent(c), nil
+}
+
+// PurgeContent permanently removes content together with its details. Its revision history is kept for auditing.
+func (r *InMemoryContentRepository) PurgeContent(ctx context.Context, id int, expectedModified *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.content[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if !matchesModified(c, expectedModified) {
+		return ErrModified
+	}
+	delete(r.content, id)
+	return nil
+}
+
+// ApplyScheduledTransitions publishes content in review whose publish_at has passed and archives published content
+// whose unpublish_at has passed, clearing the schedule that triggered the change. At most limit items are
+// transitioned per call and the transitioned content is returned.
+func (r *InMemoryContentRepository) ApplyScheduledTransitions(
+	ctx context.Context, now time.Time, limit int) ([]*model.Content, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := r.collect(func(c *model.Content) bool {
+		if c.DeletedAt != nil {
+			return false
+		}
+		return (c.Status == model.StatusReview && c.PublishAt != nil && !c.PublishAt.After(now)) ||
+			(c.Status == model.StatusPublished && c.UnpublishAt != nil && !c.UnpublishAt.After(now))
+	}, compareByID)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	transitioned := make([]*model.Content, 0, len(due))
+	for _, d := range due {
+		c := r.content[d.ID]
+		if c.Status == model.StatusReview {
+			c.Status, c.PublishAt = model.StatusPublished, nil
+		} else {
+			c.Status, c.UnpublishAt = model.StatusArchived, nil
+		}
+		c.LastModifiedDate = normalizeTime(now)
+		c.LastModifiedBy = model.SchedulerAuthor
+		r.insertRevision(c)
+		transitioned = append(transitioned, r.readContent(c))
+	}
+
+	return transitioned, nil
+}
+
+// GetRevisions returns the revision history of content, oldest first
+func (r *InMemoryContentRepository) GetRevisions(ctx context.Context, contentID int) ([]*model.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := make([]*model.Revision, 0, len(r.revisions[contentID]))
+	for _, rev := range r.revisions[contentID] {
+		revisions = append(revisions, cloneRevision(rev))
+	}
+	return revisions, nil
+}
+
+// GetRevision returns a single revision of content
+func (r *InMemoryContentRepository) GetRevision(ctx context.Context, contentID, revision int) (*model.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rev := range r.revisions[contentID] {
+		if rev.Revision == revision {
+			return cloneRevision(rev), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryContentRepository) GetContentTypeByName(ctx context.Context, name string) (*model.ContentType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, ct := range r.contentTypes {
+		if ct.Name == name {
+			return cloneContentType(ct), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryContentRepository) GetContentTypeByID(ctx context.Context, id int) (*model.ContentType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ct, ok := r.contentTypes[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneContentType(ct), nil
+}
+
+// GetContentTypes returns all content types ordered by name
+func (r *InMemoryContentRepository) GetContentTypes(ctx context.Context) ([]*model.ContentType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contentTypes := make([]*model.ContentType, 0, len(r.contentTypes))
+	for _, ct := range r.contentTypes {
+		contentTypes = append(contentTypes, cloneContentType(ct))
+	}
+	slices.SortFunc(contentTypes, func(a, b *model.ContentType) int { return strings.Compare(a.Name, b.Name) })
+	return contentTypes, nil
+}
+
+// CreateContentType inserts a new content type, returning ErrDuplicate if the name is already taken
+func (r *InMemoryContentRepository) CreateContentType(
+	ctx context.Context, contentType *model.ContentType) (*model.ContentType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.contentTypeNameTaken(contentType.Name, 0) {
+		return nil, ErrDuplicate
+	}
+
+	r.lastContentTypeID++
+	contentType.ID = r.lastContentTypeID
+	r.contentTypes[contentType.ID] = cloneContentType(contentType)
+	return contentType, nil
+}
+
+// UpdateContentType replaces the name and rules of a content type, returning ErrDuplicate if the name is already taken
+func (r *InMemoryContentRepository) UpdateContentType(ctx context.Context, contentType *model.ContentType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.contentTypes[contentType.ID]; !ok {
+		return ErrNotFound
+	}
+	if r.contentTypeNameTaken(contentType.Name, contentType.ID) {
+		return ErrDuplicate
+	}
+
+	r.contentTypes[contentType.ID] = cloneContentType(contentType)
+	return nil
+}
+
+// DeleteContentType removes a content type, returning ErrInUse if content details or revisions still reference it
+func (r *InMemoryContentRepository) DeleteContentType(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.contentTypes[id]; !ok {
+		return ErrNotFound
+	}
+
+	usesType := func(c *model.Content) bool {
+		return slices.ContainsFunc(c.Details, func(d *model.Details) bool { return d.ContentTypeID == id })
+	}
+	for _, c := range r.content {
+		if usesType(c) {
+			return ErrInUse
+		}
+	}
+	// Revisions outlive purged content, so all of them are checked rather than those of the stored content
+	for _, revisions := range r.revisions {
+		if slices.ContainsFunc(revisions, func(rev *model.Revision) bool { return usesType(rev.Snapshot) }) {
+			return ErrInUse
+		}
+	}
+
+	delete(r.contentTypes, id)
+	return nil
+}
+
+// collect returns copies of the stored content that match keep, sorted with compare. The caller must hold the lock.
+func (r *InMemoryContentRepository) collect(
+	keep func(c *model.Content) bool, compare func(a, b *model.Content) int) []*model.Content {
+	result := make([]*model.Content, 0)
+	for _, c := range r.content {
+		if keep(c) {
+			result = append(result, r.readContent(c))
+		}
+	}
+	slices.SortFunc(result, compare)
+	return result
+}
+
+// readContent returns a copy of stored content with the content type name of every detail, as joined by the SQL
+// repositories. The caller must hold the lock.
+func (r *InMemoryContentRepository) readContent(c *model.Content) *model.Content {
+	clone := cloneContent(c)
+	for _, d := range clone.Details {
+		if ct, ok := r.contentTypes[d.ContentTypeID]; ok {
+			d.ContentTypeName = ct.Name
+		}
+	}
+	return clone
+}
+
+// assignDetailIDs gives every detail of the content a new ID and its position in slice order. The caller must hold
+// the write lock.
+func (r *InMemoryContentRepository) assignDetailIDs(content *model.Content) {
+	for i, d := range content.Details {
+		r.lastDetailID++
+		d.ID = r.lastDetailID
+		d.ContentID = content.ID
+		d.Position = i
+	}
+}
+
+// insertRevision records a snapshot of the content as its next revision. The caller must hold the write lock.
+func (r *InMemoryContentRepository) insertRevision(content *model.Content) {
+	r.lastRevisionID++
+	r.revisions[content.ID] = append(r.revisions[content.ID], &model.Revision{
+		ID:        r.lastRevisionID,
+		ContentID: content.ID,
+		Revision:  len(r.revisions[content.ID]) + 1,
+		Snapshot:  cloneContent(content),
+		Author:    content.LastModifiedBy,
+		CreatedAt: content.LastModifiedDate,
+	})
+}
+
+// hasContentType reports whether any detail of the content has the named type. The caller must hold the lock.
+func (r *InMemoryContentRepository) hasContentType(c *model.Content, name string) bool {
+	return slices.ContainsFunc(c.Details, func(d *model.Details) bool {
+		ct, ok := r.contentTypes[d.ContentTypeID]
+		return ok && ct.Name == name
+	})
+}
+
+// textDetails joins the values of the text details of the content. The caller must hold the lock.
+func (r *InMemoryContentRepository) textDetails(c *model.Content) string {
+	var values []string
+	for _, d := range c.Details {
+		if ct, ok := r.contentTypes[d.ContentTypeID]; ok && ct.Name == "text" {
+			values = append(values, d.Value)
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+// contentTypeNameTaken reports whether a content type other than exceptID has the name. The caller must hold the lock.
+func (r *InMemoryContentRepository) contentTypeNameTaken(name string, exceptID int) bool {
+	for id, ct := range r.contentTypes {
+		if id != exceptID && ct.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func compareByID(a, b *model.Content) int {
+	return a.ID - b.ID
+}
+
+// compareSortField compares two content items by a sort field
+func compareSortField(sortBy SortField, a, b *model.Content) int {
+	switch sortBy {
+	case SortByName:
+		return strings.Compare(a.Name, b.Name)
+	case SortByLastModifiedDate:
+		return a.LastModifiedDate.Compare(b.LastModifiedDate)
+	default:
+		return a.CreationDate.Compare(b.CreationDate)
+	}
+}
+
+// cursorContent converts a cursor into the content position it points at, so it can be compared like any other item
+func cursorContent(sortBy SortField, cursor *Cursor) (*model.Content, error) {
+	value, err := cursor.sortValue(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &model.Content{ID: cursor.ID}
+	switch v := value.(type) {
+	case time.Time:
+		c.CreationDate, c.LastModifiedDate = v, v
+	case string:
+		c.Name = v
+	}
+	return c, nil
+}
+
+// matchesModified reports whether content was last modified at the expected time, which always holds if it is nil
+func matchesModified(c *model.Content, expectedModified *time.Time) bool {
+	return expectedModified == nil || c.LastModifiedDate.Equal(*expectedModified)
+}
+
+// normalizeTime converts a time to UTC at the microsecond precision stored by Postgres
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Round(time.Microsecond)
+}
+
+func cloneContent(c *model.Content) *model.Content {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.PublishAt = cloneTime(c.PublishAt)
+	clone.UnpublishAt = cloneTime(c.UnpublishAt)
+	clone.DeletedAt = cloneTime(c.DeletedAt)
+	clone.Details = make([]*model.Details, 0, len(c.Details))
+	for _, d := range c.Details {
+		detail := *d
+		clone.Details = append(clone.Details, &detail)
+	}
+	return &clone
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := normalizeTime(*t)
+	return &clone
+}
+
+func cloneRevision(rev *model.Revision) *model.Revision {
+	clone := *rev
+	clone.Snapshot = cloneContent(rev.Snapshot)
+	return &clone
+}
+
+func cloneContentType(ct *model.ContentType) *model.ContentType {
+	clone := *ct
+	clone.Rules.MimeTypes = slices.Clone(ct.Rules.MimeTypes)
+	return &clone
+}
@@ -0,0 +1,274 @@
+//go:build !integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/model"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var memoryTimestamp = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestContent(name string, offset time.Duration, contentTypeID int, value string) *model.Content {
+	return &model.Content{
+		Name:             name,
+		Description:      name + " description",
+		CreationDate:     memoryTimestamp.Add(offset),
+		LastModifiedDate: memoryTimestamp.Add(offset),
+		LastModifiedBy:   "editor",
+		Details:          []*model.Details{{ContentTypeID: contentTypeID, Value: value}},
+	}
+}
+
+func TestInMemoryContentRepository_Conformance(t *testing.T) {
+	testRepositoryConformance(t, func(t *testing.T) ContentRepository {
+		return NewInMemoryContentRepository()
+	})
+}
+
+func TestInMemoryContentRepository_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryContentRepository()
+
+	input := newTestContent("first", 0, 1, "hello")
+	created, err := repo.CreateContentWithDetails(ctx, input)
+	if err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+	if created.ID != 1 || created.Status != model.StatusDraft || created.Details[0].ID != 1 ||
+		created.Details[0].ContentID != 1 {
+		t.Errorf("CreateContentWithDetails() got = %+v, expected ID 1 in draft with detail ID 1", created)
+	}
+
+	// Changes to returned content must not leak into the repository
+	created.Name = "changed"
+	created.Details[0].Value = "changed"
+
+	got, err := repo.GetContentByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetContentByID() unexpected error: %v", err)
+	}
+	if got.Name != "first" || got.Details[0].Value != "hello" {
+		t.Errorf("GetContentByID() got = %+v, expected the stored content", got)
+	}
+
+	got, err = repo.GetContentByID(ctx, 2)
+	if err != nil || got != nil {
+		t.Errorf("GetContentByID() got = %v, %v, expected = nil, nil", got, err)
+	}
+
+	revisions, err := repo.GetRevisions(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetRevisions() unexpected error: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Snapshot.Name != "first" {
+		t.Errorf("GetRevisions() got = %+v, expected a single revision of the created content", revisions)
+	}
+}
+
+func TestInMemoryContentRepository_UpdateContentWithDetails(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryContentRepository()
+	if _, err := repo.CreateContentWithDetails(ctx, newTestContent("first", 0, 1, "hello")); err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+
+	update := newTestContent("updated", time.Hour, 1, "world")
+	update.ID = 1
+	update.Status = model.StatusPublished
+	updated, err := repo.UpdateContentWithDetails(ctx, update, nil)
+	if err != nil {
+		t.Fatalf("UpdateContentWithDetails() unexpected error: %v", err)
+	}
+	if !updated.CreationDate.Equal(memoryTimestamp) || updated.Status != model.StatusDraft ||
+		updated.Details[0].ID != 2 {
+		t.Errorf("UpdateContentWithDetails() got = %+v, expected creation date and status kept", updated)
+	}
+
+	update.ID = 2
+	if _, err := repo.UpdateContentWithDetails(ctx, update, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentWithDetails() error = %v, expected = %v", err, ErrNotFound)
+	}
+
+	rev, err := repo.GetRevision(ctx, 1, 2)
+	if err != nil || rev == nil || rev.Snapshot.Name != "updated" {
+		t.Errorf("GetRevision() got = %+v, %v, expected the updated snapshot", rev, err)
+	}
+}
+
+func TestInMemoryContentRepository_DeleteLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryContentRepository()
+	if _, err := repo.CreateContentWithDetails(ctx, newTestContent("first", 0, 1, "hello")); err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+
+	if err := repo.RestoreContent(ctx, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RestoreContent() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if err := repo.SoftDeleteContent(ctx, 1, memoryTimestamp, nil); err != nil {
+		t.Fatalf("SoftDeleteContent() unexpected error: %v", err)
+	}
+	if err := repo.SoftDeleteContent(ctx, 1, memoryTimestamp, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SoftDeleteContent() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if got, _ := repo.GetContentByID(ctx, 1); got != nil {
+		t.Errorf("GetContentByID() got = %v, expected = nil", got)
+	}
+	if deleted, _ := repo.GetDeletedContent(ctx); len(deleted) != 1 || deleted[0].ID != 1 {
+		t.Errorf("GetDeletedContent() got = %v, expected the deleted content", deleted)
+	}
+
+	if err := repo.RestoreContent(ctx, 1); err != nil {
+		t.Fatalf("RestoreContent() unexpected error: %v", err)
+	}
+	if all, _ := repo.GetAllContent(ctx); len(all) != 1 {
+		t.Errorf("GetAllContent() got = %v, expected the restored content", all)
+	}
+
+	if err := repo.PurgeContent(ctx, 1, nil); err != nil {
+		t.Fatalf("PurgeContent() unexpected error: %v", err)
+	}
+	if err := repo.PurgeContent(ctx, 1, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PurgeContent() error = %v, expected = %v", err, ErrNotFound)
+	}
+	if revisions, _ := repo.GetRevisions(ctx, 1); len(revisions) != 1 {
+		t.Errorf("GetRevisions() got = %v, expected the revision history to be kept", revisions)
+	}
+}
+
+func TestInMemoryContentRepository_SearchContent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryContentRepository()
+	for _, c := range []*model.Content{
+		newTestContent("Gopher guide", 0, 1, "all about go"),
+		newTestContent("Recipes", 0, 1, "a gopher cooking guide"),
+		newTestContent("Gopher photo", 0, 2, "https://example.com/gopher.png"),
+	} {
+		if _, err := repo.CreateContentWithDetails(ctx, c); err != nil {
+			t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+		}
+	}
+
+	results, err := repo.SearchContent(ctx, "gopher -photo", 10, "")
+	if err != nil {
+		t.Fatalf("SearchContent() unexpected error: %v", err)
+	}
+	ids := make([]int, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.Content.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("SearchContent() got = %v, expected = %v", ids, []int{1, 2})
+	}
+	if results[0].Snippet != "<mark>Gopher</mark> guide <mark>Gopher</mark> guide description all about go" {
+		t.Errorf("SearchContent() snippet = %q", results[0].Snippet)
+	}
+
+	results, err = repo.SearchContent(ctx, "gopher", 10, model.StatusPublished)
+	if err != nil || len(results) != 0 {
+		t.Errorf("SearchContent() got = %v, %v, expected no published results", results, err)
+	}
+}
+
+func TestInMemoryContentRepository_ContentTypes(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryContentRepository()
+
+	contentTypes, err := repo.GetContentTypes(ctx)
+	if err != nil {
+		t.Fatalf("GetContentTypes() unexpected error: %v", err)
+	}
+	if len(contentTypes) != 3 || contentTypes[0].Name != "image" || contentTypes[2].Name != "video" {
+		t.Errorf("GetContentTypes() got = %v, expected the default types ordered by name", contentTypes)
+	}
+
+	if _, err := repo.CreateContentType(ctx, &model.ContentType{Name: "text"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateContentType() error = %v, expected = %v", err, ErrDuplicate)
+	}
+	created, err := repo.CreateContentType(ctx, &model.ContentType{Name: "audio"})
+	if err != nil || created.ID != 4 {
+		t.Fatalf("CreateContentType() got = %v, %v, expected ID 4", created, err)
+	}
+	if err := repo.UpdateContentType(ctx, &model.ContentType{ID: 4, Name: "image"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("UpdateContentType() error = %v, expected = %v", err, ErrDuplicate)
+	}
+	if err := repo.UpdateContentType(ctx, &model.ContentType{ID: 5, Name: "podcast"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentType() error = %v, expected = %v", err, ErrNotFound)
+	}
+
+	if _, err := repo.CreateContentWithDetails(ctx, newTestContent("first", 0, 4, "hello")); err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+	update := newTestContent("first", 0, 1, "hello")
+	update.ID = 1
+	if _, err := repo.UpdateContentWithDetails(ctx, update, nil); err != nil {
+		t.Fatalf("UpdateContentWithDetails() unexpected error: %v", err)
+	}
+	// The first revision still references the type
+	if err := repo.DeleteContentType(ctx, 4); !errors.Is(err, ErrInUse) {
+		t.Errorf("DeleteContentType() error = %v, expected = %v", err, ErrInUse)
+	}
+	// So does the first revision of purged content, as revisions are kept
+	if err := repo.PurgeContent(ctx, 1, nil); err != nil {
+		t.Fatalf("PurgeContent() unexpected error: %v", err)
+	}
+	if err := repo.DeleteContentType(ctx, 4); !errors.Is(err, ErrInUse) {
+		t.Errorf("DeleteContentType() error = %v, expected = %v", err, ErrInUse)
+	}
+
+	if unused, err := repo.CreateContentType(ctx, &model.ContentType{Name: "podcast"}); err != nil || unused.ID != 5 {
+		t.Fatalf("CreateContentType() got = %v, %v, expected ID 5", unused, err)
+	}
+	if err := repo.DeleteContentType(ctx, 5); err != nil {
+		t.Errorf("DeleteContentType() unexpected error: %v", err)
+	}
+	if err := repo.DeleteContentType(ctx, 5); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteContentType() error = %v, expected = %v", err, ErrNotFound)
+	}
+}
+
+func TestInMemoryContentRepository_Concurrency(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryContentRepository()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			created, err := repo.CreateContentWithDetails(ctx, newTestContent(fmt.Sprintf("content %d", i), 0, 1, "hello"))
+			if err != nil {
+				t.Errorf("CreateContentWithDetails() unexpected error: %v", err)
+				return
+			}
+			if _, err := repo.UpdateContentStatus(ctx, created.ID, model.StatusDraft, model.StatusReview,
+				memoryTimestamp, "editor", nil); err != nil {
+				t.Errorf("UpdateContentStatus() unexpected error: %v", err)
+			}
+			if _, err := repo.ListContent(ctx, ContentFilter{Limit: 5, SortBy: SortByName}); err != nil {
+				t.Errorf("ListContent() unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	all, err := repo.GetAllContent(ctx)
+	if err != nil {
+		t.Fatalf("GetAllContent() unexpected error: %v", err)
+	}
+	if len(all) != 20 {
+		t.Errorf("GetAllContent() got = %d items, expected = 20", len(all))
+	}
+	for i, c := range all {
+		if c.ID != i+1 || c.Status != model.StatusReview {
+			t.Errorf("GetAllContent() got = %+v, expected ID %d in review", c, i+1)
+		}
+	}
+}
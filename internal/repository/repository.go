@@ -1,18 +1,63 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/g-stro/content-management-service/database"
+	"github.com/g-stro/content-management-service/internal/logging"
 	"github.com/g-stro/content-management-service/internal/model"
-	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// ErrNotFound is returned when a record targeted by a write operation does not exist
+var ErrNotFound = errors.New("record not found")
+
+var (
+	// ErrDuplicate is returned when a write would violate a uniqueness constraint
+	ErrDuplicate = errors.New("record already exists")
+	// ErrInUse is returned when a record cannot be deleted because other records still reference it
+	ErrInUse = errors.New("record is still referenced")
+	// ErrModified is returned when a conditional write targets content that has been modified since the expected
+	// version
+	ErrModified = errors.New("record has been modified")
+)
+
+// ContentRepository stores content, its revision history and content types.
+//
+// Read methods that look up a single record return a nil record and a nil error when it does not exist, while
+// write methods targeting a missing record return ErrNotFound. Soft-deleted content is treated as missing by every
+// method except GetDeletedContent, RestoreContent and PurgeContent. Revisions are never removed, not even when their
+// content is purged.
+//
+// Content writes taking an expectedModified time are conditional when it is not nil: they only apply while the
+// content was last modified at exactly that time, checked atomically with the write, and return ErrModified otherwise.
 type ContentRepository interface {
-	GetAllContent() ([]*model.Content, error)
-	CreateContentWithDetails(content *model.Content) (*model.Content, error)
-	GetContentTypeByName(name string) (*model.ContentType, error)
-	GetContentTypeByID(id int) (*model.ContentType, error)
+	GetAllContent(ctx context.Context) ([]*model.Content, error)
+	ListContent(ctx context.Context, filter ContentFilter) ([]*model.Content, error)
+	GetContentByID(ctx context.Context, id int) (*model.Content, error)
+	GetDeletedContent(ctx context.Context) ([]*model.Content, error)
+	SearchContent(ctx context.Context, query string, limit int, status model.ContentStatus) ([]*model.SearchResult, error)
+	CreateContentWithDetails(ctx context.Context, content *model.Content) (*model.Content, error)
+	UpdateContentWithDetails(ctx context.Context, content *model.Content, expectedModified *time.Time) (*model.Content,
+		error)
+	SoftDeleteContent(ctx context.Context, id int, deletedAt time.Time, expectedModified *time.Time) error
+	RestoreContent(ctx context.Context, id int) error
+	UpdateContentStatus(ctx context.Context, id int, from, to model.ContentStatus, modifiedAt time.Time,
+		author string, expectedModified *time.Time) (*model.Content, error)
+	PurgeContent(ctx context.Context, id int, expectedModified *time.Time) error
+	ApplyScheduledTransitions(ctx context.Context, now time.Time, limit int) ([]*model.Content, error)
+	GetRevisions(ctx context.Context, contentID int) ([]*model.Revision, error)
+	GetRevision(ctx context.Context, contentID, revision int) (*model.Revision, error)
+	GetContentTypeByName(ctx context.Context, name string) (*model.ContentType, error)
+	GetContentTypeByID(ctx context.Context, id int) (*model.ContentType, error)
+	GetContentTypes(ctx context.Context) ([]*model.ContentType, error)
+	CreateContentType(ctx context.Context, contentType *model.ContentType) (*model.ContentType, error)
+	UpdateContentType(ctx context.Context, contentType *model.ContentType) error
+	DeleteContentType(ctx context.Context, id int) error
 }
 
 type PostgresContentRepository struct {
@@ -23,139 +68,624 @@ func NewPostgresContentRepository(c *database.Connection) *PostgresContentReposi
 	return &PostgresContentRepository{conn: c}
 }
 
-func (r *PostgresContentRepository) GetAllContent() ([]*model.Content, error) {
-	query := `SELECT c.id, c.name, c.description, c.creation_date, c.last_modified_date,
-                 cd.id, cd.content_id, cd.content_type_id, cd.value
+// withTimeout bounds a repository operation by the query timeout of the connection. The caller must call the
+// returned cancel function once the operation completes.
+func (r *PostgresContentRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.conn.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.conn.QueryTimeout)
+}
+
+// contentColumns are the columns scanned by queryContent, in scan order. Details are left joined, so their columns are
+// NULL for content without details.
+const contentColumns = `c.id, c.name, c.description, c.status, c.creation_date, c.last_modified_date,
+                 c.last_modified_by, c.publish_at, c.unpublish_at, c.deleted_at,
+                 cd.id, cd.content_id, cd.content_type_id, cd.value, cd.position, ct.name`
+
+func (r *PostgresContentRepository) GetAllContent(ctx context.Context) ([]*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + contentColumns + `
+                 FROM content c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.deleted_at IS NULL
+                 ORDER BY c.id, cd.position, cd.id`
+
+	return queryContent(ctx, r.conn.DB, query)
+}
+
+// ListContent returns a single page of content matching the filter, using keyset pagination
+func (r *PostgresContentRepository) ListContent(ctx context.Context, filter ContentFilter) ([]*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	sortBy := filter.SortBy
+	if !sortBy.Valid() {
+		sortBy = SortByCreationDate
+	}
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	conditions := []string{"c.deleted_at IS NULL"}
+	if filter.Status != "" {
+		conditions = append(conditions, "c.status = "+arg(filter.Status))
+	}
+	if filter.NamePrefix != "" {
+		conditions = append(conditions, "c.name LIKE "+arg(escapeLike(filter.NamePrefix)+"%")+` ESCAPE '\'`)
+	}
+	if filter.ContentType != "" {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM content_details fd
+                 JOIN content_type ft ON ft.id = fd.content_type_id
+                 WHERE fd.content_id = c.id AND ft.name = `+arg(filter.ContentType)+`)`)
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "c.creation_date > "+arg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "c.creation_date < "+arg(*filter.CreatedBefore))
+	}
+	if filter.Cursor != nil {
+		value, err := filter.Cursor.sortValue(sortBy)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("(c.%s, c.id) %s (%s, %s)",
+			sortBy, comparison, arg(value), arg(filter.Cursor.ID)))
+	}
+
+	order := fmt.Sprintf("c.%s %s, c.id %s", sortBy, direction, direction)
+	query := `WITH page AS (
+                 SELECT c.* FROM content c
+                 WHERE ` + strings.Join(conditions, " AND ") + `
+                 ORDER BY ` + order + `
+                 LIMIT ` + arg(filter.Limit) + `)
+                 SELECT ` + contentColumns + `
+                 FROM page c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 ORDER BY ` + order + `, cd.position, cd.id`
+
+	return queryContent(ctx, r.conn.DB, query, args...)
+}
+
+func (r *PostgresContentRepository) GetContentByID(ctx context.Context, id int) (*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + contentColumns + `
                  FROM content c 
-                 JOIN content_details cd ON c.id = cd.content_id
-                 JOIN content_type ct ON ct.id = cd.content_type_id`
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.id = $1 AND c.deleted_at IS NULL
+                 ORDER BY cd.position, cd.id`
 
-	rows, err := r.conn.DB.Query(query)
+	content, err := queryContent(ctx, r.conn.DB, query, id)
 	if err != nil {
-		slog.Error("failed to execute query", "error", err)
+		return nil, err
+	}
+
+	// A nil content with a nil error signals that no content exists for the ID
+	if len(content) == 0 {
+		return nil, nil
+	}
+	return content[0], nil
+}
+
+// GetDeletedContent returns all soft-deleted content, most recently deleted first
+func (r *PostgresContentRepository) GetDeletedContent(ctx context.Context) ([]*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + contentColumns + `
+                 FROM content c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.deleted_at IS NOT NULL
+                 ORDER BY c.deleted_at DESC, c.id, cd.position, cd.id`
+
+	return queryContent(ctx, r.conn.DB, query)
+}
+
+// insertDetails inserts the details of the content within the transaction, positioned in slice order
+func insertDetails(ctx context.Context, tx *sql.Tx, content *model.Content) error {
+	stmtDetails := `
+	   INSERT INTO content_details (content_id, content_type_id, value, position)
+	   VALUES ($1, $2, $3, $4)
+	   RETURNING id`
+
+	for i, cd := range content.Details {
+		cd.ContentID = content.ID
+		cd.Position = i
+		var detailsID int
+		err := tx.QueryRowContext(ctx, stmtDetails, cd.ContentID, cd.ContentTypeID, cd.Value, cd.Position).Scan(&detailsID)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to execute details query or scan result", "error", err)
+			return err
+		}
+		cd.ID = detailsID // Set the content details ID after creation.
+	}
+	return nil
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryContent executes a query selecting contentColumns and groups the detail rows under their content,
+// preserving the order in which content first appears in the result set
+func queryContent(ctx context.Context, q querier, query string, args ...any) ([]*model.Content, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to execute query", "error", err)
 		return nil, err
 	}
 
 	defer func(rows *sql.Rows) {
 		err = rows.Close()
 		if err != nil {
-			slog.Error("failed to close rows", "error", err)
+			logging.FromContext(ctx).Error("failed to close rows", "error", err)
 		}
 	}(rows)
 
-	var contentMap = make(map[any]*model.Content)
+	var result []*model.Content
+	var contentMap = make(map[int]*model.Content)
 	for rows.Next() {
 		var content model.Content
-		var contentDetail model.Details
+		var lastModifiedBy sql.NullString
+		var publishAt, unpublishAt, deletedAt sql.NullTime
+		var detailID, detailContentID, detailContentTypeID, detailPosition sql.NullInt64
+		var detailValue, detailContentTypeName sql.NullString
 		err = rows.Scan(
-			&content.ID, &content.Name, &content.Description, &content.CreationDate, &content.LastModifiedDate,
-			&contentDetail.ID, &contentDetail.ContentID, &contentDetail.ContentTypeID, &contentDetail.Value)
+			&content.ID, &content.Name, &content.Description, &content.Status, &content.CreationDate,
+			&content.LastModifiedDate, &lastModifiedBy, &publishAt, &unpublishAt, &deletedAt, &detailID,
+			&detailContentID, &detailContentTypeID, &detailValue, &detailPosition, &detailContentTypeName)
 		if err != nil {
-			slog.Error("failed to scan rows into content and contentDetail structures", "error", err)
+			logging.FromContext(ctx).Error("failed to scan rows into content and contentDetail structures", "error", err)
 			return nil, err
 		}
 
-		// Normalize times to UTC
-		content.CreationDate = content.CreationDate.UTC()
-		content.LastModifiedDate = content.LastModifiedDate.UTC()
-
 		if _, exists := contentMap[content.ID]; !exists {
+			// Normalize times to UTC
+			content.CreationDate = content.CreationDate.UTC()
+			content.LastModifiedDate = content.LastModifiedDate.UTC()
+			content.LastModifiedBy = lastModifiedBy.String
+			content.PublishAt = nullTimeToPtr(publishAt)
+			content.UnpublishAt = nullTimeToPtr(unpublishAt)
+			content.DeletedAt = nullTimeToPtr(deletedAt)
+
 			content.Details = make([]*model.Details, 0)
 			contentMap[content.ID] = &content
+			result = append(result, &content)
+		}
+		// Content without details is returned as a single row without a detail
+		if detailID.Valid {
+			contentMap[content.ID].Details = append(contentMap[content.ID].Details, &model.Details{
+				ID:              int(detailID.Int64),
+				ContentID:       int(detailContentID.Int64),
+				ContentTypeID:   int(detailContentTypeID.Int64),
+				Value:           detailValue.String,
+				Position:        int(detailPosition.Int64),
+				ContentTypeName: detailContentTypeName.String,
+			})
 		}
-		contentMap[content.ID].Details = append(contentMap[content.ID].Details, &contentDetail)
 	}
-
-	var result []*model.Content
-	for _, content := range contentMap {
-		result = append(result, content)
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("failed to iterate rows", "error", err)
+		return nil, err
 	}
 
 	return result, nil
 }
 
-func (r *PostgresContentRepository) CreateContentWithDetails(content *model.Content) (*model.Content, error) {
-	tx, err := r.conn.DB.Begin()
+func (r *PostgresContentRepository) CreateContentWithDetails(
+	ctx context.Context, content *model.Content) (*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.conn.DB.BeginTx(ctx, nil)
 	if err != nil {
-		slog.Error("failed to start the transaction", "error", err)
+		logging.FromContext(ctx).Error("failed to start the transaction", "error", err)
 		return nil, err
 	}
 
 	defer func() {
 		if err != nil {
-			slog.Error("transaction error", "error", err)
+			logging.FromContext(ctx).Error("transaction error", "error", err)
 			err := tx.Rollback()
 			if err != nil {
-				slog.Error("failed to roll back transaction", "error", err)
+				logging.FromContext(ctx).Error("failed to roll back transaction", "error", err)
 			}
 		}
 	}()
 
+	if content.Status == "" {
+		content.Status = model.StatusDraft // New content starts its workflow as a draft
+	}
+	normalizeContentTimes(content)
+
 	stmtContent := `
-        INSERT INTO content (name, description, creation_date, last_modified_date)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO content (name, description, status, creation_date, last_modified_date, last_modified_by,
+                             publish_at, unpublish_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
         RETURNING id`
 
 	var id int
-	err = tx.QueryRow(
-		stmtContent, content.Name, content.Description, content.CreationDate, content.LastModifiedDate).Scan(&id)
+	err = tx.QueryRowContext(ctx,
+		stmtContent, content.Name, content.Description, content.Status, content.CreationDate,
+		content.LastModifiedDate, nullIfEmpty(content.LastModifiedBy), content.PublishAt, content.UnpublishAt).Scan(&id)
 	if err != nil {
-		slog.Error("failed to execute query and scan result", "error", err)
+		logging.FromContext(ctx).Error("failed to execute query and scan result", "error", err)
 		return nil, err
 	}
 
-	stmtDetails := `
-	   INSERT INTO content_details (content_id, content_type_id, value)
-	   VALUES ($1, $2, $3)
-	   RETURNING id`
+	content.ID = id // Set the content ID after creation.
 
-	for _, cd := range content.Details {
-		cd.ContentID = id
-		var detailsID int
-		err = tx.QueryRow(stmtDetails, cd.ContentID, cd.ContentTypeID, cd.Value).Scan(&detailsID)
+	err = insertDetails(ctx, tx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertRevision(ctx, tx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to commit the transaction", "error", err)
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// UpdateContentWithDetails replaces the content fields and all of its details in a single transaction.
+// The workflow status is left unchanged, see UpdateContentStatus.
+func (r *PostgresContentRepository) UpdateContentWithDetails(
+	ctx context.Context, content *model.Content, expectedModified *time.Time) (*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to start the transaction", "error", err)
+		return nil, err
+	}
+
+	defer func() {
 		if err != nil {
-			slog.Error("failed to execute details query or scan result", "error", err)
-			return nil, err
+			logging.FromContext(ctx).Error("transaction error", "error", err)
+			err := tx.Rollback()
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to roll back transaction", "error", err)
+			}
 		}
-		cd.ID = detailsID // Set the content details ID after creation.
+	}()
+
+	normalizeContentTimes(content)
+	args := []any{content.Name, content.Description, content.LastModifiedDate, nullIfEmpty(content.LastModifiedBy),
+		content.PublishAt, content.UnpublishAt, content.ID}
+	condition, args := modifiedCondition(args, expectedModified)
+	stmtContent := `
+        UPDATE content SET name = $1, description = $2, last_modified_date = $3, last_modified_by = $4,
+                           publish_at = $5, unpublish_at = $6
+        WHERE id = $7 AND deleted_at IS NULL` + condition + `
+        RETURNING creation_date, status`
+
+	var creationDate time.Time
+	err = tx.QueryRowContext(ctx, stmtContent, args...).Scan(&creationDate, &content.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = notFoundOrModified(ctx, tx, content.ID, expectedModified)
+		}
+		logging.FromContext(ctx).Error("failed to execute query and scan result", "error", err)
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM content_details WHERE content_id = $1", content.ID)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to delete existing content details", "error", err)
+		return nil, err
+	}
+
+	err = insertDetails(ctx, tx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	content.CreationDate = creationDate.UTC()
+
+	err = insertRevision(ctx, tx, content)
+	if err != nil {
+		return nil, err
 	}
 
 	// commit the transaction
 	err = tx.Commit()
 	if err != nil {
-		slog.Error("failed to commit the transaction", "error", err)
+		logging.FromContext(ctx).Error("failed to commit the transaction", "error", err)
 		return nil, err
 	}
 
-	content.ID = id // Set the content ID after creation.
-
 	return content, nil
 }
 
-func (r *PostgresContentRepository) GetContentTypeByName(name string) (*model.ContentType, error) {
-	var contentType model.ContentType
-	query := "SELECT id, name FROM content_type WHERE name = $1"
-	err := r.conn.DB.QueryRow(query, name).Scan(&contentType.ID, &contentType.Name)
+// UpdateContentStatus moves content from one workflow status to another. The update only applies while the
+// content is still in the expected status, otherwise ErrNotFound is returned.
+func (r *PostgresContentRepository) UpdateContentStatus(ctx context.Context, id int, from, to model.ContentStatus,
+	modifiedAt time.Time, author string, expectedModified *time.Time) (*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to start the transaction", "error", err)
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			logging.FromContext(ctx).Error("transaction error", "error", err)
+			err := tx.Rollback()
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to roll back transaction", "error", err)
+			}
+		}
+	}()
+
+	args := []any{to, normalizeTime(modifiedAt), nullIfEmpty(author), id, from}
+	condition, args := modifiedCondition(args, expectedModified)
+	stmt := `
+        UPDATE content SET status = $1, last_modified_date = $2, last_modified_by = $3
+        WHERE id = $4 AND status = $5 AND deleted_at IS NULL` + condition
+
+	res, err := tx.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to update content status", "error", err)
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read affected rows", "error", err)
+		return nil, err
+	}
+	if affected == 0 {
+		err = notFoundOrModified(ctx, tx, id, expectedModified)
+		return nil, err
+	}
+
+	query := `SELECT ` + contentColumns + `
+                 FROM content c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.id = $1
+                 ORDER BY cd.position, cd.id`
+
+	content, err := queryContent(ctx, tx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		err = ErrNotFound
+		return nil, err
+	}
+
+	err = insertRevision(ctx, tx, content[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to commit the transaction", "error", err)
+		return nil, err
+	}
+
+	return content[0], nil
+}
+
+// SoftDeleteContent marks content as deleted without removing it, so it can later be restored
+func (r *PostgresContentRepository) SoftDeleteContent(
+	ctx context.Context, id int, deletedAt time.Time, expectedModified *time.Time) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	condition, args := modifiedCondition([]any{normalizeTime(deletedAt), id}, expectedModified)
+	query := "UPDATE content SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL" + condition
+	err := r.execAffectingOne(ctx, query, args...)
+	if errors.Is(err, ErrNotFound) {
+		// The update was atomic, this only tells the caller why it did not apply
+		return notFoundOrModified(ctx, r.conn.DB, id, expectedModified)
+	}
+	return err
+}
+
+// RestoreContent clears the deletion mark of soft-deleted content
+func (r *PostgresContentRepository) RestoreContent(ctx context.Context, id int) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE content SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL"
+	return r.execAffectingOne(ctx, query, id)
+}
+
+// PurgeContent permanently removes content together with its details. Its revision history is kept for auditing.
+func (r *PostgresContentRepository) PurgeContent(ctx context.Context, id int, expectedModified *time.Time) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to start the transaction", "error", err)
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			logging.FromContext(ctx).Error("transaction error", "error", err)
+			err := tx.Rollback()
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to roll back transaction", "error", err)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM content_details WHERE content_id = $1", id)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to delete content details", "error", err)
+		return err
+	}
+
+	condition, args := modifiedCondition([]any{id}, expectedModified)
+	res, err := tx.ExecContext(ctx, "DELETE FROM content WHERE id = $1"+condition, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to delete content", "error", err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read affected rows", "error", err)
+		return err
+	}
+	if affected == 0 {
+		err = notFoundOrModified(ctx, tx, id, expectedModified)
+		return err
+	}
+
+	// commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to commit the transaction", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// nullIfEmpty converts an empty string into a SQL NULL
+func nullIfEmpty(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTimeToPtr converts a nullable SQL timestamp into a UTC time pointer
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
+
+// normalizeContentTimes converts every time of the content to UTC at the microsecond precision stored by the
+// database, so that the content returned by a write carries the same times as the stored row
+func normalizeContentTimes(content *model.Content) {
+	content.CreationDate = normalizeTime(content.CreationDate)
+	content.LastModifiedDate = normalizeTime(content.LastModifiedDate)
+	content.PublishAt = cloneTime(content.PublishAt)
+	content.UnpublishAt = cloneTime(content.UnpublishAt)
+	content.DeletedAt = cloneTime(content.DeletedAt)
+}
+
+// escapeLike escapes the LIKE wildcard characters in s so it is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// modifiedCondition returns the condition restricting a content write to the expected last modification date, to be
+// appended to its WHERE clause, and args with the date appended. Unconditional writes get an empty condition.
+func modifiedCondition(args []any, expectedModified *time.Time) (string, []any) {
+	if expectedModified == nil {
+		return "", args
+	}
+	args = append(args, normalizeTime(*expectedModified))
+	return fmt.Sprintf(" AND last_modified_date = $%d", len(args)), args
+}
+
+// notFoundOrModified returns the reason a content write affected no rows: ErrModified if it was conditional and the
+// content still exists, and ErrNotFound otherwise
+func notFoundOrModified(ctx context.Context, q querier, id int, expectedModified *time.Time) error {
+	if expectedModified == nil {
+		return ErrNotFound
+	}
+
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM content WHERE id = $1 AND deleted_at IS NULL)",
+		id).Scan(&exists)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to check whether content exists", "error", err)
+		return err
+	}
+	if exists {
+		return ErrModified
+	}
+	return ErrNotFound
+}
+
+// execAffectingOne executes a statement and returns ErrNotFound if it did not affect any row
+func (r *PostgresContentRepository) execAffectingOne(ctx context.Context, query string, args ...any) error {
+	res, err := r.conn.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to execute statement", "error", err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to read affected rows", "error", err)
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresContentRepository) GetContentTypeByName(ctx context.Context, name string) (*model.ContentType, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, name, rules FROM content_type WHERE name = $1"
+	contentType, err := scanContentType(ctx, r.conn.DB.QueryRowContext(ctx, query, name))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
-		slog.Error("failed to fetch ContentType", "error", err)
+		logging.FromContext(ctx).Error("failed to fetch ContentType", "error", err)
 		return nil, err
 	}
-	return &contentType, nil
+	return contentType, nil
 }
 
-func (r *PostgresContentRepository) GetContentTypeByID(id int) (*model.ContentType, error) {
-	var contentType model.ContentType
-	query := "SELECT id, name FROM content_type WHERE id = $1"
-	err := r.conn.DB.QueryRow(query, id).Scan(&contentType.ID, &contentType.Name)
+func (r *PostgresContentRepository) GetContentTypeByID(ctx context.Context, id int) (*model.ContentType, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, name, rules FROM content_type WHERE id = $1"
+	contentType, err := scanContentType(ctx, r.conn.DB.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
-		slog.Error("failed to fetch ContentType", "error", err)
+		logging.FromContext(ctx).Error("failed to fetch ContentType", "error", err)
 		return nil, err
 	}
-	return &contentType, nil
+	return contentType, nil
 }
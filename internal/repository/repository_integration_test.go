@@ -3,6 +3,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/g-stro/content-management-service/database"
 	"github.com/g-stro/content-management-service/internal/model"
@@ -59,8 +61,8 @@ func TestPostgresContentRepository_GetAllContent(t *testing.T) {
 				return err
 			},
 			expected: []*model.Content{
-				{ID: 1, Name: testName, Description: testDescription, CreationDate: staticTimestamp,
-					LastModifiedDate: staticTimestamp, Details: []*model.Details{{ID: 1, ContentID: 1, ContentTypeID: 1, Value: "test text"}}},
+				{ID: 1, Name: testName, Description: testDescription, Status: model.StatusDraft, CreationDate: staticTimestamp,
+					LastModifiedDate: staticTimestamp, Details: []*model.Details{{ID: 1, ContentID: 1, ContentTypeID: 1, Value: "test text", ContentTypeName: "text"}}},
 			},
 			wantErr: false,
 		},
@@ -74,12 +76,12 @@ func TestPostgresContentRepository_GetAllContent(t *testing.T) {
 
 			// Clean the database
 			defer func() {
-				if _, err := conn.DB.Exec("DELETE FROM content_details; DELETE FROM content;"); err != nil {
+				if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
 					t.Fatalf("Failed to clean up database: %v", err)
 				}
 			}()
 
-			content, err := repo.GetAllContent()
+			content, err := repo.GetAllContent(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllContent() error = %v, expected error = %v", err, tt.wantErr)
 				return
@@ -111,8 +113,8 @@ func TestPostgresContentRepository_CreateContentWithDetails(t *testing.T) {
 			name: "successful creation",
 			input: &model.Content{Name: testName, Description: testDescription, CreationDate: staticTimestamp,
 				LastModifiedDate: staticTimestamp, Details: []*model.Details{{ContentTypeID: 1, Value: "test text"}}},
-			expected: &model.Content{ID: 2, Name: testName, Description: testDescription, CreationDate: staticTimestamp,
-				LastModifiedDate: staticTimestamp, Details: []*model.Details{{ID: 2, ContentID: 2, ContentTypeID: 1, Value: "test text"}}},
+			expected: &model.Content{ID: 2, Name: testName, Description: testDescription, Status: model.StatusDraft,
+				CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp, Details: []*model.Details{{ID: 2, ContentID: 2, ContentTypeID: 1, Value: "test text"}}},
 			wantErr: false,
 		},
 	}
@@ -120,7 +122,7 @@ func TestPostgresContentRepository_CreateContentWithDetails(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Call repository method
-			content, err := repo.CreateContentWithDetails(tt.input)
+			content, err := repo.CreateContentWithDetails(context.Background(), tt.input)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateContent() error = %v, expected error = %v", err, tt.wantErr)
 				return
@@ -128,7 +130,7 @@ func TestPostgresContentRepository_CreateContentWithDetails(t *testing.T) {
 
 			// Clean the database
 			defer func() {
-				if _, err := conn.DB.Exec("DELETE FROM content_details; DELETE FROM content;"); err != nil {
+				if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
 					t.Fatalf("Failed to clean up database: %v", err)
 				}
 			}()
@@ -153,8 +155,639 @@ func TestPostgresContentRepository_CreateContentWithDetails(t *testing.T) {
 	}
 }
 
+func TestPostgresContentRepository_GetContentByID(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	var id int
+	err = conn.DB.QueryRow(
+		`INSERT INTO content (name, description, creation_date, last_modified_date) 
+		VALUES ($1, $2, $3, $4) 
+		RETURNING id`,
+		testName, testDescription, staticTimestamp, staticTimestamp).Scan(&id)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	var detailsID int
+	err = conn.DB.QueryRow(
+		`INSERT INTO content_details (content_id, content_type_id, value)
+		VALUES ($1, $2, $3)
+		RETURNING id`,
+		id, 1, "test text").Scan(&detailsID)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	tests := []struct {
+		name     string
+		id       int
+		expected *model.Content
+		wantErr  bool
+	}{
+		{
+			name: "successful fetch",
+			id:   id,
+			expected: &model.Content{ID: id, Name: testName, Description: testDescription, Status: model.StatusDraft,
+				CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp, Details: []*model.Details{{ID: detailsID, ContentID: id, ContentTypeID: 1, Value: "test text", ContentTypeName: "text"}}},
+			wantErr: false,
+		},
+		{
+			name:     "content not found",
+			id:       0,
+			expected: nil, // Expect nil response
+			wantErr:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := repo.GetContentByID(context.Background(), tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetContentByID() error = %v, expected error = %v", err, tt.wantErr)
+				return
+			}
+
+			if !isContentSliceEqual([]*model.Content{tt.expected}, []*model.Content{content}) {
+				t.Errorf("GetContentByID() got: \n%+v\nexpected:\n%+v", content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostgresContentRepository_UpdateContentWithDetails(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	created, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: testName, Description: testDescription,
+		CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp,
+		Details: []*model.Details{{ContentTypeID: 1, Value: "test text"}}})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	updatedTimestamp := staticTimestamp.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		input     *model.Content
+		wantErr   error
+		wantValue string
+	}{
+		{
+			name: "successful update",
+			input: &model.Content{ID: created.ID, Name: "updated name", Description: "updated description",
+				LastModifiedDate: updatedTimestamp, Details: []*model.Details{{ContentTypeID: 2, Value: "updated image"}}},
+			wantValue: "updated image",
+		},
+		{
+			name:    "content not found",
+			input:   &model.Content{ID: 0, Name: "updated name", LastModifiedDate: updatedTimestamp},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.UpdateContentWithDetails(context.Background(), tt.input, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateContentWithDetails() error = %v, expected error = %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			content, err := repo.GetContentByID(context.Background(), tt.input.ID)
+			if err != nil || content == nil {
+				t.Fatalf("GetContentByID() content = %v, error = %v", content, err)
+			}
+
+			if content.Name != tt.input.Name || content.Description != tt.input.Description ||
+				!content.CreationDate.Equal(staticTimestamp) || !content.LastModifiedDate.Equal(updatedTimestamp) {
+				t.Errorf("UpdateContentWithDetails() got: \n%+v\nexpected:\n%+v", *content, *tt.input)
+			}
+
+			if len(content.Details) != 1 || content.Details[0].Value != tt.wantValue {
+				t.Errorf("UpdateContentWithDetails() got details: \n%+v", printSlice(content.Details))
+			}
+		})
+	}
+}
+
+func TestPostgresContentRepository_SoftDeleteRestoreAndPurge(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	created, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: testName, Description: testDescription,
+		CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp,
+		Details: []*model.Details{{ContentTypeID: 1, Value: "test text"}}})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	deletedTimestamp := staticTimestamp.Add(time.Hour)
+	if err = repo.SoftDeleteContent(context.Background(), created.ID, deletedTimestamp, nil); err != nil {
+		t.Fatalf("SoftDeleteContent() error = %v", err)
+	}
+	if err = repo.SoftDeleteContent(context.Background(), created.ID, deletedTimestamp, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SoftDeleteContent() twice error = %v, expected error = %v", err, ErrNotFound)
+	}
+
+	if content, err := repo.GetContentByID(context.Background(), created.ID); err != nil || content != nil {
+		t.Errorf("GetContentByID() after delete got = %v, error = %v, expected nil", content, err)
+	}
+
+	deleted, err := repo.GetDeletedContent(context.Background())
+	if err != nil {
+		t.Fatalf("GetDeletedContent() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].DeletedAt == nil || !deleted[0].DeletedAt.Equal(deletedTimestamp) {
+		t.Errorf("GetDeletedContent() got: \n%+v", printSlice(deleted))
+	}
+
+	if err = repo.RestoreContent(context.Background(), created.ID); err != nil {
+		t.Fatalf("RestoreContent() error = %v", err)
+	}
+	if content, err := repo.GetContentByID(context.Background(), created.ID); err != nil || content == nil {
+		t.Errorf("GetContentByID() after restore got = %v, error = %v", content, err)
+	}
+
+	if err = repo.PurgeContent(context.Background(), created.ID, nil); err != nil {
+		t.Fatalf("PurgeContent() error = %v", err)
+	}
+	if err = repo.PurgeContent(context.Background(), created.ID, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("PurgeContent() twice error = %v, expected error = %v", err, ErrNotFound)
+	}
+
+	var detailsCount int
+	if err = conn.DB.QueryRow("SELECT COUNT(*) FROM content_details WHERE content_id = $1", created.ID).Scan(&detailsCount); err != nil {
+		t.Fatalf("failed to count content details: %v", err)
+	}
+	if detailsCount != 0 {
+		t.Errorf("PurgeContent() left %d content details behind", detailsCount)
+	}
+}
+
+func TestPostgresContentRepository_ListContent(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	ids := make(map[string]int)
+	for i, name := range []string{"charlie", "alpha", "bravo"} {
+		created, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: name, Description: testDescription,
+			CreationDate: staticTimestamp.Add(time.Duration(i) * time.Hour), LastModifiedDate: staticTimestamp,
+			Details: []*model.Details{{ContentTypeID: 1 + i%2, Value: "test value"}}})
+		if err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		ids[name] = created.ID
+	}
+	createdAfter := staticTimestamp
+
+	tests := []struct {
+		name     string
+		filter   ContentFilter
+		expected []string
+	}{
+		{
+			name:     "sorted by name",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName},
+			expected: []string{"alpha", "bravo", "charlie"},
+		},
+		{
+			name:     "sorted by creation date descending",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByCreationDate, Descending: true},
+			expected: []string{"bravo", "alpha", "charlie"},
+		},
+		{
+			name:     "limited page",
+			filter:   ContentFilter{Limit: 2, SortBy: SortByName},
+			expected: []string{"alpha", "bravo"},
+		},
+		{
+			name: "page after cursor",
+			filter: ContentFilter{Limit: 2, SortBy: SortByName,
+				Cursor: &Cursor{SortBy: SortByName, Value: "bravo", ID: ids["bravo"]}},
+			expected: []string{"charlie"},
+		},
+		{
+			name:     "name prefix",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, NamePrefix: "br"},
+			expected: []string{"bravo"},
+		},
+		{
+			name:     "content type",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, ContentType: "image"},
+			expected: []string{"alpha"},
+		},
+		{
+			name:     "created after",
+			filter:   ContentFilter{Limit: 10, SortBy: SortByName, CreatedAfter: &createdAfter},
+			expected: []string{"alpha", "bravo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := repo.ListContent(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("ListContent() error = %v", err)
+			}
+
+			names := make([]string, 0)
+			for _, c := range content {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("ListContent() got = %v, expected = %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostgresContentRepository_SearchContent(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	fixtures := []*model.Content{
+		{Name: "Gardening guide", Description: "Growing tomatoes",
+			Details: []*model.Details{{ContentTypeID: 1, Value: "Water the plants daily"}}},
+		{Name: "Cooking basics", Description: "Tomatoes in the kitchen",
+			Details: []*model.Details{{ContentTypeID: 1, Value: "Slice and season"}}},
+		{Name: "Holiday photos", Description: "Beach trip",
+			Details: []*model.Details{{ContentTypeID: 2, Value: "https://example.com/tomatoes.png"}}},
+	}
+	for _, f := range fixtures {
+		f.CreationDate, f.LastModifiedDate = staticTimestamp, staticTimestamp
+		if _, err := repo.CreateContentWithDetails(context.Background(), f); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "matches name",
+			query:    "gardening",
+			expected: []string{"Gardening guide"},
+		},
+		{
+			name:     "matches description with stemming",
+			query:    "tomato",
+			expected: []string{"Gardening guide", "Cooking basics"},
+		},
+		{
+			name:     "matches text detail values only",
+			query:    "season",
+			expected: []string{"Cooking basics"},
+		},
+		{
+			name:     "no matches",
+			query:    "spaceship",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.SearchContent(context.Background(), tt.query, 10, "")
+			if err != nil {
+				t.Fatalf("SearchContent() error = %v", err)
+			}
+
+			names := make([]string, 0)
+			for _, r := range results {
+				names = append(names, r.Content.Name)
+				if r.Rank <= 0 || !strings.Contains(r.Snippet, "<mark>") {
+					t.Errorf("SearchContent() result %q has rank %v and snippet %q", r.Content.Name, r.Rank, r.Snippet)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("SearchContent() got = %v, expected = %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostgresContentRepository_Revisions(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	created, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: testName, Description: testDescription,
+		CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp, LastModifiedBy: "alice",
+		Details: []*model.Details{{ContentTypeID: 1, Value: "test text"}}})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	_, err = repo.UpdateContentWithDetails(context.Background(), &model.Content{ID: created.ID, Name: "updated name",
+		Description: testDescription, LastModifiedDate: staticTimestamp.Add(time.Hour), LastModifiedBy: "bob",
+		Details: []*model.Details{{ContentTypeID: 1, Value: "updated text"}}}, nil)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	revisions, err := repo.GetRevisions(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("GetRevisions() error = %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("GetRevisions() got %d revisions, expected 2", len(revisions))
+	}
+
+	expected := []struct {
+		revision int
+		author   string
+		name     string
+		value    string
+	}{
+		{revision: 1, author: "alice", name: testName, value: "test text"},
+		{revision: 2, author: "bob", name: "updated name", value: "updated text"},
+	}
+	for i, exp := range expected {
+		rev := revisions[i]
+		if rev.Revision != exp.revision || rev.Author != exp.author || rev.Snapshot.Name != exp.name ||
+			len(rev.Snapshot.Details) != 1 || rev.Snapshot.Details[0].Value != exp.value {
+			t.Errorf("GetRevisions()[%d] got = %+v, snapshot = %+v", i, *rev, *rev.Snapshot)
+		}
+	}
+
+	rev, err := repo.GetRevision(context.Background(), created.ID, 2)
+	if err != nil || rev == nil || rev.Snapshot.Name != "updated name" {
+		t.Errorf("GetRevision() got = %v, error = %v", rev, err)
+	}
+
+	rev, err = repo.GetRevision(context.Background(), created.ID, 3)
+	if err != nil || rev != nil {
+		t.Errorf("GetRevision() of missing revision got = %v, error = %v, expected nil", rev, err)
+	}
+}
+
+func TestPostgresContentRepository_UpdateContentStatus(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	created, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: testName, Description: testDescription,
+		CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp,
+		Details: []*model.Details{{ContentTypeID: 1, Value: "test text"}}})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if created.Status != model.StatusDraft {
+		t.Errorf("CreateContentWithDetails() status = %q, expected = %q", created.Status, model.StatusDraft)
+	}
+
+	modifiedTimestamp := staticTimestamp.Add(time.Hour)
+	content, err := repo.UpdateContentStatus(context.Background(), created.ID, model.StatusDraft, model.StatusReview, modifiedTimestamp, "bob", nil)
+	if err != nil {
+		t.Fatalf("UpdateContentStatus() error = %v", err)
+	}
+	if content.Status != model.StatusReview || content.LastModifiedBy != "bob" ||
+		!content.LastModifiedDate.Equal(modifiedTimestamp) || len(content.Details) != 1 {
+		t.Errorf("UpdateContentStatus() got = %+v", *content)
+	}
+
+	// The expected status no longer matches
+	_, err = repo.UpdateContentStatus(context.Background(), created.ID, model.StatusDraft, model.StatusReview, modifiedTimestamp, "bob", nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentStatus() error = %v, expected error = %v", err, ErrNotFound)
+	}
+
+	listed, err := repo.ListContent(context.Background(), ContentFilter{Limit: 10, SortBy: SortByName, Status: model.StatusPublished})
+	if err != nil || len(listed) != 0 {
+		t.Errorf("ListContent() of published content got = %v, error = %v, expected none", listed, err)
+	}
+
+	revisions, err := repo.GetRevisions(context.Background(), created.ID)
+	if err != nil || len(revisions) != 2 || revisions[1].Snapshot.Status != model.StatusReview {
+		t.Errorf("GetRevisions() got = %v, error = %v, expected a revision for the transition", revisions, err)
+	}
+}
+
+func TestPostgresContentRepository_ApplyScheduledTransitions(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	// Clean the database
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	now := staticTimestamp.Add(time.Hour)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	create := func(name string, publishAt, unpublishAt *time.Time, status model.ContentStatus) *model.Content {
+		created, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: name, Description: testDescription,
+			CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp, PublishAt: publishAt, UnpublishAt: unpublishAt,
+			Details: []*model.Details{{ContentTypeID: 1, Value: "test text"}}})
+		if err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		if status != model.StatusDraft {
+			if _, err := repo.UpdateContentStatus(context.Background(), created.ID, model.StatusDraft, status, staticTimestamp, "bob", nil); err != nil {
+				t.Fatalf("Setup failed: %v", err)
+			}
+		}
+		return created
+	}
+	due := create("due", &past, nil, model.StatusReview)
+	create("not due", &future, nil, model.StatusReview)
+	create("draft", &past, nil, model.StatusDraft)
+
+	transitioned, err := repo.ApplyScheduledTransitions(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("ApplyScheduledTransitions() error = %v", err)
+	}
+	if len(transitioned) != 1 || transitioned[0].ID != due.ID || transitioned[0].Status != model.StatusPublished ||
+		transitioned[0].PublishAt != nil || transitioned[0].LastModifiedBy != model.SchedulerAuthor {
+		t.Errorf("ApplyScheduledTransitions() got = %v, expected only %d to be published", printSlice(transitioned), due.ID)
+	}
+
+	// Transitions are applied once
+	transitioned, err = repo.ApplyScheduledTransitions(context.Background(), now, 10)
+	if err != nil || len(transitioned) != 0 {
+		t.Errorf("ApplyScheduledTransitions() second run got = %v, error = %v, expected none", printSlice(transitioned), err)
+	}
+
+	revisions, err := repo.GetRevisions(context.Background(), due.ID)
+	if err != nil || len(revisions) != 3 || revisions[2].Author != model.SchedulerAuthor {
+		t.Errorf("GetRevisions() got = %v, error = %v, expected a revision by the scheduler", revisions, err)
+	}
+}
+
+func TestPostgresContentRepository_ContentTypes(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	repo := NewPostgresContentRepository(conn)
+
+	// Clean the database, keeping the seeded content types
+	defer func() {
+		if _, err := conn.DB.Exec("DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content; " +
+			"DELETE FROM content_type WHERE id > 3;"); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}()
+
+	created, err := repo.CreateContentType(context.Background(), &model.ContentType{Name: "quote", Rules: model.ValueRules{MaxLength: 280}})
+	if err != nil || created.ID <= 3 {
+		t.Fatalf("CreateContentType() got = %v, error = %v", created, err)
+	}
+
+	fetched, err := repo.GetContentTypeByID(context.Background(), created.ID)
+	if err != nil || fetched == nil || fetched.Rules.MaxLength != 280 {
+		t.Errorf("GetContentTypeByID() got = %v, error = %v, expected the stored rules", fetched, err)
+	}
+	seeded, err := repo.GetContentTypeByName(context.Background(), "image")
+	if err != nil || seeded == nil || seeded.Rules.Format != model.FormatURL {
+		t.Errorf("GetContentTypeByName() got = %v, error = %v, expected the seeded url rules", seeded, err)
+	}
+
+	_, err = repo.CreateContentType(context.Background(), &model.ContentType{Name: "quote"})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Errorf("CreateContentType() of duplicate error = %v, expected error = %v", err, ErrDuplicate)
+	}
+
+	err = repo.UpdateContentType(context.Background(), &model.ContentType{ID: created.ID, Name: "text"})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Errorf("UpdateContentType() to taken name error = %v, expected error = %v", err, ErrDuplicate)
+	}
+	err = repo.UpdateContentType(context.Background(), &model.ContentType{ID: created.ID, Name: "pull-quote"})
+	if err != nil {
+		t.Errorf("UpdateContentType() error = %v", err)
+	}
+	err = repo.UpdateContentType(context.Background(), &model.ContentType{ID: 9999, Name: "missing"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateContentType() of missing type error = %v, expected error = %v", err, ErrNotFound)
+	}
+
+	contentTypes, err := repo.GetContentTypes(context.Background())
+	if err != nil || len(contentTypes) != 4 || contentTypes[1].Name != "pull-quote" {
+		t.Errorf("GetContentTypes() got = %v, error = %v", printSlice(contentTypes), err)
+	}
+
+	// A referenced type cannot be deleted
+	content, err := repo.CreateContentWithDetails(context.Background(), &model.Content{Name: testName, Description: testDescription,
+		CreationDate: staticTimestamp, LastModifiedDate: staticTimestamp,
+		Details: []*model.Details{{ContentTypeID: created.ID, Value: "To be or not to be"}}})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := repo.DeleteContentType(context.Background(), created.ID); !errors.Is(err, ErrInUse) {
+		t.Errorf("DeleteContentType() of used type error = %v, expected error = %v", err, ErrInUse)
+	}
+
+	if err := repo.PurgeContent(context.Background(), content.ID, nil); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := repo.DeleteContentType(context.Background(), created.ID); err != nil {
+		t.Errorf("DeleteContentType() error = %v", err)
+	}
+	if err := repo.DeleteContentType(context.Background(), created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteContentType() of missing type error = %v, expected error = %v", err, ErrNotFound)
+	}
+}
+
 // TestPostgresContentRepository_GetContentTypeByName tests the GetContentTypeByName method of PostgresContentRepository
-// with data already seeded from sql/sql.sql
+// with data already seeded by the initial migration
 func TestPostgresContentRepository_GetContentTypeByName(t *testing.T) {
 	conn, err := database.NewConnection()
 	if err != nil {
@@ -188,7 +821,7 @@ func TestPostgresContentRepository_GetContentTypeByName(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Call repository method
-			contentType, err := repo.GetContentTypeByName(tt.contentTypeName)
+			contentType, err := repo.GetContentTypeByName(context.Background(), tt.contentTypeName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetContentTypeByName() error = %v, expected error = %v", err, tt.wantErr)
 				return
@@ -208,7 +841,7 @@ func TestPostgresContentRepository_GetContentTypeByName(t *testing.T) {
 }
 
 // TestPostgresContentRepository_GetContentTypeByID tests the GetContentTypeByID method of PostgresContentRepository
-// with data already seeded from sql/sql.sql
+// with data already seeded by the initial migration
 func TestPostgresContentRepository_GetContentTypeByID(t *testing.T) {
 	conn, err := database.NewConnection()
 	if err != nil {
@@ -242,7 +875,7 @@ func TestPostgresContentRepository_GetContentTypeByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Call repository method
-			contentType, err := repo.GetContentTypeByID(tt.id)
+			contentType, err := repo.GetContentTypeByID(context.Background(), tt.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetContentTypeByID() error = %v, expected error = %v", err, tt.wantErr)
 				return
@@ -261,14 +894,6 @@ func TestPostgresContentRepository_GetContentTypeByID(t *testing.T) {
 	}
 }
 
-func printSlice[T any](items []*T) string {
-	var builder strings.Builder
-	for _, item := range items {
-		builder.WriteString(fmt.Sprintf("%+v\n", *item))
-	}
-	return builder.String()
-}
-
 func isSliceEqual[T any](expected, actual []T, compare func(e, a T) bool) bool {
 	if len(expected) != len(actual) {
 		return false
@@ -305,3 +930,24 @@ func isContentSliceEqual(expected, actual []*model.Content) bool {
 		return reflect.DeepEqual(*exp, *act)
 	})
 }
+
+func TestPostgresContentRepository_Conformance(t *testing.T) {
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	defer conn.DB.Close()
+
+	clean := func(t *testing.T) {
+		if _, err := conn.DB.Exec(`DELETE FROM content_revision; DELETE FROM content_details; DELETE FROM content;
+                 DELETE FROM content_type WHERE id > 3;`); err != nil {
+			t.Fatalf("Failed to clean up database: %v", err)
+		}
+	}
+
+	testRepositoryConformance(t, func(t *testing.T) ContentRepository {
+		clean(t)
+		t.Cleanup(func() { clean(t) })
+		return NewPostgresContentRepository(conn)
+	})
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+)
+
+// insertRevision records an immutable snapshot of the content as its next revision within the transaction.
+// The content row is locked by the preceding write, which serializes revision numbering per content.
+func insertRevision(ctx context.Context, tx *sql.Tx, content *model.Content) error {
+	snapshot, err := json.Marshal(content)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to marshal content snapshot", "error", err)
+		return err
+	}
+
+	stmt := `
+        INSERT INTO content_revision (content_id, revision, snapshot, author, created_at)
+        SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4
+        FROM content_revision WHERE content_id = $1`
+
+	_, err = tx.ExecContext(
+		ctx, stmt, content.ID, string(snapshot), nullIfEmpty(content.LastModifiedBy), content.LastModifiedDate)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to insert content revision", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetRevisions returns the revision history of content, oldest first
+func (r *PostgresContentRepository) GetRevisions(ctx context.Context, contentID int) ([]*model.Revision, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, content_id, revision, snapshot, author, created_at
+                 FROM content_revision
+                 WHERE content_id = $1
+                 ORDER BY revision`
+
+	rows, err := r.conn.DB.QueryContext(ctx, query, contentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to execute query", "error", err)
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to close rows", "error", err)
+		}
+	}(rows)
+
+	revisions := make([]*model.Revision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("failed to iterate rows", "error", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns a single revision of content
+func (r *PostgresContentRepository) GetRevision(ctx context.Context, contentID, revision int) (*model.Revision, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, content_id, revision, snapshot, author, created_at
+                 FROM content_revision
+                 WHERE content_id = $1 AND revision = $2`
+
+	rev, err := scanRevision(ctx, r.conn.DB.QueryRowContext(ctx, query, contentID, revision))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+// scanRevision scans a content_revision row and decodes its snapshot
+func scanRevision(ctx context.Context, row interface{ Scan(dest ...any) error }) (*model.Revision, error) {
+	var revision model.Revision
+	var snapshot []byte
+	var author sql.NullString
+	err := row.Scan(&revision.ID, &revision.ContentID, &revision.Revision, &snapshot, &author, &revision.CreatedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logging.FromContext(ctx).Error("failed to scan row into revision structure", "error", err)
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		logging.FromContext(ctx).Error("failed to unmarshal revision snapshot", "error", err)
+		return nil, err
+	}
+	revision.Author = author.String
+	revision.CreatedAt = revision.CreatedAt.UTC()
+
+	return &revision, nil
+}
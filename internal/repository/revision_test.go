@@ -0,0 +1,52 @@
+//go:build !integration
+
+package repository
+
+import (
+	"encoding/json"
+	"github.com/g-stro/content-management-service/internal/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// storedSnapshot is a revision snapshot in the format stored since revisions were introduced
+const storedSnapshot = `{"ID":7,"Name":"Name","Description":"Description","Status":"published",` +
+	`"CreationDate":"2025-03-01T12:00:00Z","LastModifiedDate":"2025-03-01T13:00:00Z","LastModifiedBy":"alice",` +
+	`"PublishAt":null,"UnpublishAt":"2025-04-01T00:00:00Z","DeletedAt":null,` +
+	`"Details":[{"ID":3,"ContentID":7,"ContentTypeID":2,"Value":"https://example.com/a.png","Position":0}]}`
+
+func TestRevisionSnapshotFormat(t *testing.T) {
+	unpublishAt := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	expected := &model.Content{
+		ID:               7,
+		Name:             "Name",
+		Description:      "Description",
+		Status:           model.StatusPublished,
+		CreationDate:     conformanceTimestamp,
+		LastModifiedDate: conformanceTimestamp.Add(time.Hour),
+		LastModifiedBy:   "alice",
+		UnpublishAt:      &unpublishAt,
+		Details: []*model.Details{
+			{ID: 3, ContentID: 7, ContentTypeID: 2, Value: "https://example.com/a.png"},
+		},
+	}
+
+	// Stored snapshots must still decode, and new snapshots must keep the keys queried by DeleteContentType
+	var decoded *model.Content
+	if err := json.Unmarshal([]byte(storedSnapshot), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("json.Unmarshal() got = %+v, expected = %+v", decoded, expected)
+	}
+
+	expected.Details[0].ContentTypeName = "image" // Joined on reads, never part of a snapshot
+	encoded, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	if string(encoded) != storedSnapshot {
+		t.Errorf("json.Marshal() got = %s, expected = %s", encoded, storedSnapshot)
+	}
+}
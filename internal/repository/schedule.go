@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+	"time"
+)
+
+// dueQuery selects and locks content whose scheduled publish or unpublish time has passed. Rows already locked by another
+// replica are skipped, so each scheduled transition is applied exactly once.
+const dueQuery = `
+        SELECT id, status FROM content
+        WHERE deleted_at IS NULL
+          AND ((status = 'review' AND publish_at <= $1) OR (status = 'published' AND unpublish_at <= $1))
+        ORDER BY id
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED`
+
+// ApplyScheduledTransitions publishes content in review whose publish_at has passed and archives published content
+// whose unpublish_at has passed, clearing the schedule that triggered the change. At most limit items are
+// transitioned per call and the transitioned content is returned.
+func (r *PostgresContentRepository) ApplyScheduledTransitions(
+	ctx context.Context, now time.Time, limit int) ([]*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return applyScheduledTransitions(ctx, r.conn.DB, dueQuery, now, limit)
+}
+
+// applyScheduledTransitions applies the scheduled transitions of the content selected by dueQuery in a single
+// transaction. The query takes the current time and the limit as arguments and must lock the rows it returns.
+func applyScheduledTransitions(
+	ctx context.Context, db *sql.DB, dueQuery string, now time.Time, limit int) ([]*model.Content, error) {
+	now = normalizeTime(now)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to start the transaction", "error", err)
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			logging.FromContext(ctx).Error("transaction error", "error", err)
+			err := tx.Rollback()
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to roll back transaction", "error", err)
+			}
+		}
+	}()
+
+	due, err := lockDueContent(ctx, tx, dueQuery, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtPublish := `
+        UPDATE content SET status = 'published', publish_at = NULL, last_modified_date = $1, last_modified_by = $2
+        WHERE id = $3`
+	stmtUnpublish := `
+        UPDATE content SET status = 'archived', unpublish_at = NULL, last_modified_date = $1, last_modified_by = $2
+        WHERE id = $3`
+
+	query := `SELECT ` + contentColumns + `
+                 FROM content c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.id = $1
+                 ORDER BY cd.position, cd.id`
+
+	transitioned := make([]*model.Content, 0, len(due))
+	for _, d := range due {
+		stmt := stmtPublish
+		if d.status == model.StatusPublished {
+			stmt = stmtUnpublish
+		}
+
+		_, err = tx.ExecContext(ctx, stmt, now, model.SchedulerAuthor, d.id)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to apply scheduled transition", "error", err, "content_id", d.id)
+			return nil, err
+		}
+
+		// The row is locked by the transaction, so the content is always found, with or without details
+		var content []*model.Content
+		content, err = queryContent(ctx, tx, query, d.id)
+		if err != nil {
+			return nil, err
+		}
+
+		err = insertRevision(ctx, tx, content[0])
+		if err != nil {
+			return nil, err
+		}
+		transitioned = append(transitioned, content[0])
+	}
+
+	// commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to commit the transaction", "error", err)
+		return nil, err
+	}
+
+	return transitioned, nil
+}
+
+// dueContent identifies content due for a scheduled transition
+type dueContent struct {
+	id     int
+	status model.ContentStatus
+}
+
+// lockDueContent locks the content due for a scheduled transition and returns it with its current status
+func lockDueContent(ctx context.Context, tx *sql.Tx, dueQuery string, now time.Time, limit int) ([]dueContent, error) {
+	rows, err := tx.QueryContext(ctx, dueQuery, now, limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to execute query", "error", err)
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to close rows", "error", err)
+		}
+	}(rows)
+
+	var due []dueContent
+	for rows.Next() {
+		var d dueContent
+		if err = rows.Scan(&d.id, &d.status); err != nil {
+			logging.FromContext(ctx).Error("failed to scan due content", "error", err)
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("failed to iterate rows", "error", err)
+		return nil, err
+	}
+
+	return due, nil
+}
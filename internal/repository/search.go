@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/lib/pq"
+	"html"
+	"strings"
+)
+
+// searchQuery ranks content against a web search style query. The document combines the content name (weight A),
+// description (weight B) and the values of its text details (weight C). Matches in the snippet are delimited by the
+// control characters of snippetMarks, which are removed from the text beforehand, see markSnippet.
+const searchQuery = `
+        WITH q AS (SELECT websearch_to_tsquery('english', $1) AS query),
+        docs AS (
+            SELECT c.id,
+                   setweight(to_tsvector('english', coalesce(c.name, '')), 'A') ||
+                   setweight(to_tsvector('english', coalesce(c.description, '')), 'B') ||
+                   setweight(to_tsvector('english', coalesce(string_agg(cd.value, ' '), '')), 'C') AS document,
+                   translate(concat_ws(' ', c.name, c.description, string_agg(cd.value, ' ')),
+                             chr(2) || chr(3), '') AS body
+            FROM content c
+            LEFT JOIN content_details cd ON cd.content_id = c.id
+                 AND cd.content_type_id IN (SELECT id FROM content_type WHERE name = 'text')
+            WHERE c.deleted_at IS NULL AND ($3::text = '' OR c.status = $3::text)
+            GROUP BY c.id)
+        SELECT d.id, ts_rank(d.document, q.query) AS rank,
+               ts_headline('english', d.body, q.query,
+                   'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxWords=35, MinWords=15, MaxFragments=2')
+                   AS snippet
+        FROM docs d, q
+        WHERE d.document @@ q.query
+        ORDER BY rank DESC, d.id
+        LIMIT $2`
+
+// snippetMarks replaces the control characters delimiting matches in snippets of searchQuery with <mark> tags
+var snippetMarks = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+// markSnippet turns a snippet of searchQuery into HTML. The text is escaped before the matches are wrapped in <mark>
+// tags, so that markup stored in content is shown as text.
+func markSnippet(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
+
+// SearchContent performs a full-text search over content names, descriptions and text details,
+// returning at most limit results ordered by relevance. An empty status searches content in any workflow status.
+func (r *PostgresContentRepository) SearchContent(
+	ctx context.Context, query string, limit int, status model.ContentStatus) ([]*model.SearchResult, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn.DB.QueryContext(ctx, searchQuery, query, limit, string(status))
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to execute search query", "error", err)
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to close rows", "error", err)
+		}
+	}(rows)
+
+	var ids []int64
+	var results []*model.SearchResult
+	for rows.Next() {
+		var id int64
+		var result model.SearchResult
+		err = rows.Scan(&id, &result.Rank, &result.Snippet)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to scan rows into search result structure", "error", err)
+			return nil, err
+		}
+		result.Snippet = markSnippet(result.Snippet)
+		ids = append(ids, id)
+		results = append(results, &result)
+	}
+	if err = rows.Err(); err != nil {
+		logging.FromContext(ctx).Error("failed to iterate rows", "error", err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	contentQuery := `SELECT ` + contentColumns + `
+                 FROM content c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.id = ANY($1)
+                 ORDER BY c.id, cd.position, cd.id`
+
+	content, err := queryContent(ctx, r.conn.DB, contentQuery, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	contentByID := make(map[int]*model.Content, len(content))
+	for _, c := range content {
+		contentByID[c.ID] = c
+	}
+
+	// Keep the relevance order, dropping matches whose content could not be loaded
+	matched := make([]*model.SearchResult, 0, len(results))
+	for i, result := range results {
+		if c, ok := contentByID[int(ids[i])]; ok {
+			result.Content = c
+			matched = append(matched, result)
+		}
+	}
+
+	return matched, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/database"
+	"github.com/g-stro/content-management-service/internal/model"
+	"strings"
+	"time"
+)
+
+// sqliteDueQuery selects content whose scheduled publish or unpublish time has passed. SQLite has no row locks;
+// transactions take the database write lock when they begin, so only one transition batch runs at a time.
+const sqliteDueQuery = `
+        SELECT id, status FROM content
+        WHERE deleted_at IS NULL
+          AND ((status = 'review' AND publish_at <= $1) OR (status = 'published' AND unpublish_at <= $1))
+        ORDER BY id
+        LIMIT $2`
+
+// sqliteContentTypeInUseQuery reports whether content details or revision snapshots reference a content type.
+// Snapshots are queried by the json tags of model.Content and model.Details.
+const sqliteContentTypeInUseQuery = `
+        SELECT EXISTS (SELECT 1 FROM content_details WHERE content_type_id = $1)
+            OR EXISTS (SELECT 1 FROM content_revision r, json_each(r.snapshot, '$.Details') d
+                       WHERE json_extract(d.value, '$.ContentTypeID') = $1)`
+
+// SQLiteContentRepository stores content in a local SQLite database file, for deployments without Postgres.
+//
+// SQLite accepts the same SQL as Postgres for most operations, so the repository reuses PostgresContentRepository
+// and only overrides the methods relying on row locks, JSONB or full-text search. SQLite stores times as text and
+// compares them as strings, so every time is normalized to UTC before it is written or compared.
+type SQLiteContentRepository struct {
+	*PostgresContentRepository
+}
+
+func NewSQLiteContentRepository(c *database.Connection) *SQLiteContentRepository {
+	return &SQLiteContentRepository{PostgresContentRepository: NewPostgresContentRepository(c)}
+}
+
+// ListContent returns a single page of content matching the filter, using keyset pagination
+func (r *SQLiteContentRepository) ListContent(ctx context.Context, filter ContentFilter) ([]*model.Content, error) {
+	filter.CreatedAfter = cloneTime(filter.CreatedAfter)
+	filter.CreatedBefore = cloneTime(filter.CreatedBefore)
+	return r.PostgresContentRepository.ListContent(ctx, filter)
+}
+
+// SearchContent performs a simple full-text search over content names, descriptions and text details, see
+// rankContent. Every candidate is loaded and ranked in memory, which suits the small databases SQLite is used for.
+func (r *SQLiteContentRepository) SearchContent(
+	ctx context.Context, query string, limit int, status model.ContentStatus) ([]*model.SearchResult, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	contentTypes, err := r.GetContentTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	textTypes := make(map[int]bool)
+	for _, ct := range contentTypes {
+		textTypes[ct.ID] = ct.Name == "text"
+	}
+
+	candidateQuery := `SELECT ` + contentColumns + `
+                 FROM content c 
+                 LEFT JOIN content_details cd ON c.id = cd.content_id
+                 LEFT JOIN content_type ct ON ct.id = cd.content_type_id
+                 WHERE c.deleted_at IS NULL AND ($1 = '' OR c.status = $1)
+                 ORDER BY c.id, cd.position, cd.id`
+
+	candidates, err := queryContent(ctx, r.conn.DB, candidateQuery, string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	textDetails := func(c *model.Content) string {
+		var values []string
+		for _, d := range c.Details {
+			if textTypes[d.ContentTypeID] {
+				values = append(values, d.Value)
+			}
+		}
+		return strings.Join(values, " ")
+	}
+	return rankContent(candidates, textDetails, query, limit), nil
+}
+
+// ApplyScheduledTransitions publishes content in review whose publish_at has passed and archives published content
+// whose unpublish_at has passed, clearing the schedule that triggered the change. At most limit items are
+// transitioned per call and the transitioned content is returned.
+func (r *SQLiteContentRepository) ApplyScheduledTransitions(
+	ctx context.Context, now time.Time, limit int) ([]*model.Content, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return applyScheduledTransitions(ctx, r.conn.DB, sqliteDueQuery, now, limit)
+}
+
+// DeleteContentType removes a content type, returning ErrInUse if content details or revisions still reference it
+func (r *SQLiteContentRepository) DeleteContentType(ctx context.Context, id int) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return deleteContentType(ctx, r.conn.DB, "SELECT id FROM content_type WHERE id = $1", sqliteContentTypeInUseQuery, id)
+}
@@ -0,0 +1,106 @@
+//go:build !integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/g-stro/content-management-service/database"
+	"github.com/g-stro/content-management-service/database/migrations"
+	"github.com/g-stro/content-management-service/internal/migrate"
+	"github.com/g-stro/content-management-service/internal/model"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newSQLiteRepository returns a repository backed by a migrated SQLite database file in a temporary directory
+func newSQLiteRepository(t *testing.T) *SQLiteContentRepository {
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "content.db"))
+
+	conn, err := database.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to establish database connection: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	fsys, err := migrations.ForDriver(conn.Driver)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	migrator, err := migrate.New(conn.DB, conn.Driver, fsys)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	return NewSQLiteContentRepository(conn)
+}
+
+func TestSQLiteContentRepository_Conformance(t *testing.T) {
+	testRepositoryConformance(t, func(t *testing.T) ContentRepository {
+		return newSQLiteRepository(t)
+	})
+}
+
+func TestSQLiteContentRepository_SearchContent(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepository(t)
+	for _, c := range []*model.Content{
+		newTestContent("Gopher guide", 0, 1, "all about go"),
+		newTestContent("Recipes", 0, 1, "a gopher cooking guide"),
+		newTestContent("Gopher photo", 0, 2, "https://example.com/gopher.png"),
+	} {
+		if _, err := repo.CreateContentWithDetails(ctx, c); err != nil {
+			t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+		}
+	}
+
+	results, err := repo.SearchContent(ctx, "gopher guide", 10, "")
+	if err != nil {
+		t.Fatalf("SearchContent() unexpected error: %v", err)
+	}
+	ids := make([]int, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r.Content.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("SearchContent() got = %v, expected = %v", ids, []int{1, 2})
+	}
+	snippet := "Recipes Recipes description a <mark>gopher</mark> cooking <mark>guide</mark>"
+	if len(results) > 1 && results[1].Snippet != snippet {
+		t.Errorf("SearchContent() snippet = %q", results[1].Snippet)
+	}
+
+	results, err = repo.SearchContent(ctx, "gopher", 10, model.StatusPublished)
+	if err != nil || len(results) != 0 {
+		t.Errorf("SearchContent() got = %v, %v, expected no published results", results, err)
+	}
+}
+
+func TestSQLiteContentRepository_CanceledContext(t *testing.T) {
+	repo := newSQLiteRepository(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.GetContentTypes(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetContentTypes() got = %v, expected = %v", err, context.Canceled)
+	}
+	_, err := repo.CreateContentWithDetails(ctx, newTestContent("Canceled", 0, 1, "text"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateContentWithDetails() got = %v, expected = %v", err, context.Canceled)
+	}
+}
+
+func TestSQLiteContentRepository_QueryTimeout(t *testing.T) {
+	t.Setenv("DB_QUERY_TIMEOUT", "1ns")
+	repo := newSQLiteRepository(t)
+
+	if _, err := repo.GetContentTypes(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetContentTypes() got = %v, expected = %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"cmp"
+	"github.com/g-stro/content-management-service/internal/model"
+	"html"
+	"slices"
+	"strings"
+)
+
+// rankContent performs a simple full-text search for repositories without a full-text search engine. Content
+// matches when every term of the query matches a word of its name, description or text details, given by
+// textDetails. Unlike Postgres words are not stemmed: a term matches any word it is a prefix of. Matches are ranked
+// with the default weights of ts_rank and at most limit results are returned, most relevant first.
+func rankContent(
+	candidates []*model.Content, textDetails func(c *model.Content) string, query string,
+	limit int) []*model.SearchResult {
+	include, exclude := parseSearchTerms(query)
+	results := make([]*model.SearchResult, 0)
+	if len(include) == 0 {
+		return results
+	}
+
+	for _, c := range candidates {
+		text := textDetails(c)
+		fields := []struct {
+			value  string
+			weight float64
+		}{{c.Name, 1.0}, {c.Description, 0.4}, {text, 0.2}} // The default weights of ts_rank for A, B and C
+
+		var rank float64
+		matchedAll := true
+		for _, term := range include {
+			var termRank float64
+			for _, f := range fields {
+				termRank += f.weight * float64(countTermMatches(f.value, term))
+			}
+			if termRank == 0 {
+				matchedAll = false
+				break
+			}
+			rank += termRank
+		}
+		if !matchedAll || slices.ContainsFunc(exclude, func(term string) bool {
+			return countTermMatches(c.Name+" "+c.Description+" "+text, term) > 0
+		}) {
+			continue
+		}
+
+		body := strings.Join(slices.DeleteFunc([]string{c.Name, c.Description, text}, func(s string) bool {
+			return s == ""
+		}), " ")
+		results = append(results, &model.SearchResult{Content: c, Rank: rank, Snippet: highlight(body, include)})
+	}
+
+	slices.SortFunc(results, func(a, b *model.SearchResult) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		return a.Content.ID - b.Content.ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// parseSearchTerms splits a web search style query into lowercase terms to include and terms prefixed with "-" to
+// exclude. Quotes and the "or" operator are ignored.
+func parseSearchTerms(query string) (include, exclude []string) {
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		negated := strings.HasPrefix(word, "-")
+		word = strings.Trim(word, `-"'.,;:!?()`)
+		if word == "" || word == "or" {
+			continue
+		}
+		if negated {
+			exclude = append(exclude, word)
+		} else {
+			include = append(include, word)
+		}
+	}
+	return include, exclude
+}
+
+// countTermMatches counts the words of s that start with the lowercase term
+func countTermMatches(s, term string) int {
+	n := 0
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		if strings.HasPrefix(strings.Trim(word, `"'.,;:!?()`), term) {
+			n++
+		}
+	}
+	return n
+}
+
+// highlight wraps the words of s matching any of the terms in <mark> tags, keeping at most 35 words starting a few
+// words before the first match, like the snippets produced by Postgres. The snippet is HTML, so the words are
+// escaped and markup stored in content is shown as text.
+func highlight(s string, terms []string) string {
+	words := strings.Fields(s)
+	first := -1
+	for i, word := range words {
+		lower := strings.ToLower(strings.Trim(word, `"'.,;:!?()`))
+		words[i] = html.EscapeString(word)
+		if slices.ContainsFunc(terms, func(term string) bool { return strings.HasPrefix(lower, term) }) {
+			words[i] = "<mark>" + words[i] + "</mark>"
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+
+	start := max(first-5, 0)
+	end := min(start+35, len(words))
+	return strings.Join(words[start:end], " ")
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"time"
+)
+
+// TracedContentRepository records a span for every call to the repository it wraps, with the number of rows
+// returned. Calls are only traced within a traced request.
+type TracedContentRepository struct {
+	repo   ContentRepository
+	system string
+}
+
+// NewTracedContentRepository wraps repo, naming the database system, e.g. postgres, in the attributes of its spans
+func NewTracedContentRepository(repo ContentRepository, system string) *TracedContentRepository {
+	return &TracedContentRepository{repo: repo, system: system}
+}
+
+// start starts the span of a repository method
+func (r *TracedContentRepository) start(ctx context.Context, method string) (context.Context, *tracing.Span) {
+	ctx, span := tracing.Start(ctx, "repository."+method)
+	span.SetAttribute("db.system", r.system)
+	span.SetAttribute("db.operation", method)
+	return ctx, span
+}
+
+// endSpan records the outcome of a call and ends its span. A negative number of rows is not recorded.
+func endSpan(span *tracing.Span, rows int, err error) {
+	if rows >= 0 {
+		span.SetAttribute("db.rows", rows)
+	}
+	span.SetError(err)
+	span.End()
+}
+
+// countRow returns the number of rows of a method returning a single record, which is nil when it does not exist
+func countRow[T any](record *T) int {
+	if record == nil {
+		return 0
+	}
+	return 1
+}
+
+func (r *TracedContentRepository) GetAllContent(ctx context.Context) ([]*model.Content, error) {
+	ctx, span := r.start(ctx, "GetAllContent")
+	result, err := r.repo.GetAllContent(ctx)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) ListContent(ctx context.Context, filter ContentFilter) ([]*model.Content, error) {
+	ctx, span := r.start(ctx, "ListContent")
+	result, err := r.repo.ListContent(ctx, filter)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetContentByID(ctx context.Context, id int) (*model.Content, error) {
+	ctx, span := r.start(ctx, "GetContentByID")
+	span.SetAttribute("content.id", id)
+	result, err := r.repo.GetContentByID(ctx, id)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetDeletedContent(ctx context.Context) ([]*model.Content, error) {
+	ctx, span := r.start(ctx, "GetDeletedContent")
+	result, err := r.repo.GetDeletedContent(ctx)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) SearchContent(
+	ctx context.Context, query string, limit int, status model.ContentStatus) ([]*model.SearchResult, error) {
+	ctx, span := r.start(ctx, "SearchContent")
+	result, err := r.repo.SearchContent(ctx, query, limit, status)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) CreateContentWithDetails(
+	ctx context.Context, content *model.Content) (*model.Content, error) {
+	ctx, span := r.start(ctx, "CreateContentWithDetails")
+	result, err := r.repo.CreateContentWithDetails(ctx, content)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) UpdateContentWithDetails(
+	ctx context.Context, content *model.Content, expectedModified *time.Time) (*model.Content, error) {
+	ctx, span := r.start(ctx, "UpdateContentWithDetails")
+	span.SetAttribute("content.id", content.ID)
+	result, err := r.repo.UpdateContentWithDetails(ctx, content, expectedModified)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) SoftDeleteContent(
+	ctx context.Context, id int, deletedAt time.Time, expectedModified *time.Time) error {
+	ctx, span := r.start(ctx, "SoftDeleteContent")
+	span.SetAttribute("content.id", id)
+	err := r.repo.SoftDeleteContent(ctx, id, deletedAt, expectedModified)
+	endSpan(span, -1, err)
+	return err
+}
+
+func (r *TracedContentRepository) RestoreContent(ctx context.Context, id int) error {
+	ctx, span := r.start(ctx, "RestoreContent")
+	span.SetAttribute("content.id", id)
+	err := r.repo.RestoreContent(ctx, id)
+	endSpan(span, -1, err)
+	return err
+}
+
+func (r *TracedContentRepository) UpdateContentStatus(
+	ctx context.Context, id int, from, to model.ContentStatus, modifiedAt time.Time,
+	author string, expectedModified *time.Time) (*model.Content, error) {
+	ctx, span := r.start(ctx, "UpdateContentStatus")
+	span.SetAttribute("content.id", id)
+	result, err := r.repo.UpdateContentStatus(ctx, id, from, to, modifiedAt, author, expectedModified)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) PurgeContent(ctx context.Context, id int, expectedModified *time.Time) error {
+	ctx, span := r.start(ctx, "PurgeContent")
+	span.SetAttribute("content.id", id)
+	err := r.repo.PurgeContent(ctx, id, expectedModified)
+	endSpan(span, -1, err)
+	return err
+}
+
+func (r *TracedContentRepository) ApplyScheduledTransitions(
+	ctx context.Context, now time.Time, limit int) ([]*model.Content, error) {
+	ctx, span := r.start(ctx, "ApplyScheduledTransitions")
+	result, err := r.repo.ApplyScheduledTransitions(ctx, now, limit)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetRevisions(ctx context.Context, contentID int) ([]*model.Revision, error) {
+	ctx, span := r.start(ctx, "GetRevisions")
+	span.SetAttribute("content.id", contentID)
+	result, err := r.repo.GetRevisions(ctx, contentID)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetRevision(ctx context.Context, contentID, revision int) (*model.Revision, error) {
+	ctx, span := r.start(ctx, "GetRevision")
+	span.SetAttribute("content.id", contentID)
+	result, err := r.repo.GetRevision(ctx, contentID, revision)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetContentTypeByName(ctx context.Context, name string) (*model.ContentType, error) {
+	ctx, span := r.start(ctx, "GetContentTypeByName")
+	result, err := r.repo.GetContentTypeByName(ctx, name)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetContentTypeByID(ctx context.Context, id int) (*model.ContentType, error) {
+	ctx, span := r.start(ctx, "GetContentTypeByID")
+	result, err := r.repo.GetContentTypeByID(ctx, id)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) GetContentTypes(ctx context.Context) ([]*model.ContentType, error) {
+	ctx, span := r.start(ctx, "GetContentTypes")
+	result, err := r.repo.GetContentTypes(ctx)
+	endSpan(span, len(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) CreateContentType(
+	ctx context.Context, contentType *model.ContentType) (*model.ContentType, error) {
+	ctx, span := r.start(ctx, "CreateContentType")
+	result, err := r.repo.CreateContentType(ctx, contentType)
+	endSpan(span, countRow(result), err)
+	return result, err
+}
+
+func (r *TracedContentRepository) UpdateContentType(ctx context.Context, contentType *model.ContentType) error {
+	ctx, span := r.start(ctx, "UpdateContentType")
+	err := r.repo.UpdateContentType(ctx, contentType)
+	endSpan(span, -1, err)
+	return err
+}
+
+func (r *TracedContentRepository) DeleteContentType(ctx context.Context, id int) error {
+	ctx, span := r.start(ctx, "DeleteContentType")
+	err := r.repo.DeleteContentType(ctx, id)
+	endSpan(span, -1, err)
+	return err
+}
@@ -0,0 +1,66 @@
+//go:build !integration
+
+package repository
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"testing"
+	"time"
+)
+
+type recordingExporter struct {
+	spans []tracing.SpanData
+}
+
+func (e *recordingExporter) ExportSpan(span tracing.SpanData) {
+	e.spans = append(e.spans, span)
+}
+
+func TestTracedContentRepository(t *testing.T) {
+	exporter := &recordingExporter{}
+	ctx, root := tracing.NewTracer(exporter).Start(context.Background(), "test", tracing.SpanContext{})
+	repo := NewTracedContentRepository(NewInMemoryContentRepository(), "memory")
+
+	if _, err := repo.CreateContentWithDetails(ctx, newTestContent("first", 0, 1, "hello")); err != nil {
+		t.Fatalf("CreateContentWithDetails() unexpected error: %v", err)
+	}
+	if _, err := repo.ListContent(ctx, ContentFilter{Limit: 10}); err != nil {
+		t.Fatalf("ListContent() unexpected error: %v", err)
+	}
+	if err := repo.SoftDeleteContent(ctx, 2, time.Now(), nil); err == nil {
+		t.Fatal("SoftDeleteContent() expected an error")
+	}
+	// Untraced calls are not recorded
+	if _, err := repo.GetContentByID(context.Background(), 1); err != nil {
+		t.Fatalf("GetContentByID() unexpected error: %v", err)
+	}
+	root.End()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		failed     bool
+	}{
+		{name: "repository.CreateContentWithDetails", attributes: map[string]any{"db.rows": 1}},
+		{name: "repository.ListContent", attributes: map[string]any{"db.rows": 1, "db.system": "memory"}},
+		{name: "repository.SoftDeleteContent", attributes: map[string]any{"content.id": 2}, failed: true},
+	}
+	if len(exporter.spans) != len(tests)+1 {
+		t.Fatalf("exported spans got = %d, expected = %d", len(exporter.spans), len(tests)+1)
+	}
+	for i, tt := range tests {
+		span := exporter.spans[i]
+		if span.Name != tt.name || span.ParentSpanID != root.SpanContext().SpanID {
+			t.Errorf("span %d got = %s, expected = %s as a child of the root span", i, span.Name, tt.name)
+		}
+		for key, expected := range tt.attributes {
+			if span.Attributes[key] != expected {
+				t.Errorf("%s attribute %s got = %v, expected = %v", tt.name, key, span.Attributes[key], expected)
+			}
+		}
+		if (span.Err != nil) != tt.failed {
+			t.Errorf("%s error got = %v, expected failed = %v", tt.name, span.Err, tt.failed)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Runner applies the scheduled work due at the time of the call, returning the number of items processed
+type Runner interface {
+	RunScheduledTransitions(ctx context.Context) (int, error)
+}
+
+// Scheduler periodically invokes a Runner in a background goroutine
+type Scheduler struct {
+	runner   Runner
+	interval time.Duration
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running bool
+
+	// The outcome of the last run, guarded separately because Stop holds mu while a run completes
+	statusMu sync.Mutex
+	lastRun  time.Time
+	lastErr  error
+}
+
+// ErrNotRunning is reported by Check when the scheduler has not been started or has been stopped
+var ErrNotRunning = errors.New("scheduler is not running")
+
+func NewScheduler(runner Runner, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		runner:   runner,
+		interval: interval,
+	}
+}
+
+// Start runs the scheduler until Stop is called. Starting a running scheduler has no effect.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return
+	}
+	s.running = true
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.loop(ctx, s.done)
+}
+
+// Stop signals the scheduler to stop and waits for it to exit. An in-progress run is cancelled; its transaction is
+// rolled back and the work is picked up again by the next start.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.running = false
+}
+
+// Check reports whether the scheduler is running and its last run succeeded
+func (s *Scheduler) Check(_ context.Context) error {
+	s.mu.Lock()
+	running := s.running
+	s.mu.Unlock()
+	if !running {
+		return ErrNotRunning
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if s.lastErr != nil {
+		return fmt.Errorf("last run at %s failed: %w", s.lastRun.UTC().Format(time.RFC3339), s.lastErr)
+	}
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.run(ctx) // Catch up on anything that became due while the service was down
+	for {
+		select {
+		case <-ticker.C:
+			s.run(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	n, err := s.runner.RunScheduledTransitions(ctx)
+	if errors.Is(err, context.Canceled) {
+		return // Stopped mid-run
+	}
+
+	s.statusMu.Lock()
+	s.lastRun, s.lastErr = time.Now(), err
+	s.statusMu.Unlock()
+
+	if err != nil {
+		slog.Error("failed to run scheduled transitions", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Info("scheduled transitions applied", "count", n)
+	}
+}
@@ -0,0 +1,101 @@
+//go:build !integration
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingRunner struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (r *countingRunner) RunScheduledTransitions(_ context.Context) (int, error) {
+	r.calls.Add(1)
+	return 1, r.err
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "successful runs"},
+		{name: "runner errors do not stop the scheduler", err: errors.New("runner error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &countingRunner{err: tt.err}
+			s := NewScheduler(runner, time.Millisecond)
+
+			s.Start()
+			s.Start() // Starting twice has no effect
+			deadline := time.Now().Add(time.Second)
+			for runner.calls.Load() < 3 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			s.Stop()
+
+			calls := runner.calls.Load()
+			if calls < 3 {
+				t.Fatalf("expected at least 3 runs, got %d", calls)
+			}
+
+			// No runs happen after Stop returns
+			time.Sleep(10 * time.Millisecond)
+			if after := runner.calls.Load(); after != calls {
+				t.Errorf("expected no runs after Stop, got %d more", after-calls)
+			}
+
+			s.Stop() // Stopping twice has no effect
+		})
+	}
+}
+
+func TestScheduler_Check(t *testing.T) {
+	runnerErr := errors.New("runner error")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "last run succeeded"},
+		{name: "last run failed", err: runnerErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Only the run on start happens within the test
+			runner := &countingRunner{err: tt.err}
+			s := NewScheduler(runner, time.Hour)
+
+			if err := s.Check(context.Background()); !errors.Is(err, ErrNotRunning) {
+				t.Errorf("Check() before Start got = %v, expected = %v", err, ErrNotRunning)
+			}
+
+			s.Start()
+			ran := func() bool {
+				s.statusMu.Lock()
+				defer s.statusMu.Unlock()
+				return !s.lastRun.IsZero()
+			}
+			deadline := time.Now().Add(time.Second)
+			for !ran() && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if err := s.Check(context.Background()); !errors.Is(err, tt.err) {
+				t.Errorf("Check() got = %v, expected = %v", err, tt.err)
+			}
+
+			s.Stop()
+			if err := s.Check(context.Background()); !errors.Is(err, ErrNotRunning) {
+				t.Errorf("Check() after Stop got = %v, expected = %v", err, ErrNotRunning)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/repository"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"regexp"
+	"strings"
+)
+
+// contentTypeNamePattern restricts content type names to short lowercase identifiers, e.g. "quote" or "code-block"
+var contentTypeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+// GetContentTypes returns all content types
+func (s *Service) GetContentTypes(ctx context.Context) ([]*dto.ContentType, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetContentTypes")
+	defer span.End()
+
+	contentTypes, err := s.repo.GetContentTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*dto.ContentType, 0, len(contentTypes))
+	for _, ct := range contentTypes {
+		resp = append(resp, convertContentTypeModelToDTO(ct))
+	}
+
+	return resp, nil
+}
+
+// GetContentType returns a single content type by its ID
+func (s *Service) GetContentType(ctx context.Context, id int) (*dto.ContentType, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetContentType")
+	defer span.End()
+	span.SetAttribute("content_type.id", id)
+
+	ct, err := s.repo.GetContentTypeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ct == nil {
+		return nil, ErrContentTypeNotFound
+	}
+
+	return convertContentTypeModelToDTO(ct), nil
+}
+
+// CreateContentType adds a new content type that content details can then use
+func (s *Service) CreateContentType(ctx context.Context, req dto.ContentType) (*dto.ContentType, error) {
+	ctx, span := tracing.Start(ctx, "Service.CreateContentType")
+	defer span.End()
+
+	ct, err := convertContentTypeDTOToModel(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ct, err = s.repo.CreateContentType(ctx, ct)
+	if err != nil {
+		return nil, mapContentTypeError(err)
+	}
+	s.contentTypes.invalidate()
+
+	return convertContentTypeModelToDTO(ct), nil
+}
+
+// UpdateContentType replaces the name and value rules of a content type. Content details reference types by ID, so
+// existing content keeps its type; new rules apply the next time that content is written.
+func (s *Service) UpdateContentType(ctx context.Context, id int, req dto.ContentType) (*dto.ContentType, error) {
+	ctx, span := tracing.Start(ctx, "Service.UpdateContentType")
+	defer span.End()
+	span.SetAttribute("content_type.id", id)
+
+	ct, err := convertContentTypeDTOToModel(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ct.ID = id
+	if err := s.repo.UpdateContentType(ctx, ct); err != nil {
+		return nil, mapContentTypeError(err)
+	}
+	s.contentTypes.invalidate()
+
+	return convertContentTypeModelToDTO(ct), nil
+}
+
+// DeleteContentType removes a content type that is no longer used by any content
+func (s *Service) DeleteContentType(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "Service.DeleteContentType")
+	defer span.End()
+	span.SetAttribute("content_type.id", id)
+
+	if err := s.repo.DeleteContentType(ctx, id); err != nil {
+		return mapContentTypeError(err)
+	}
+	s.contentTypes.invalidate()
+	return nil
+}
+
+// normalizeContentTypeName trims and lowercases a content type name and checks that it is well-formed
+func normalizeContentTypeName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !contentTypeNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: name must be 1-50 lowercase letters, digits, '-' or '_', starting with a letter",
+			ErrInvalidContentType)
+	}
+	return name, nil
+}
+
+// mapContentTypeError translates repository errors for content type writes into service errors
+func mapContentTypeError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrContentTypeNotFound
+	case errors.Is(err, repository.ErrDuplicate):
+		return ErrContentTypeExists
+	case errors.Is(err, repository.ErrInUse):
+		return ErrContentTypeInUse
+	default:
+		return err
+	}
+}
+
+// convertContentTypeDTOToModel normalizes and validates a requested content type
+func convertContentTypeDTOToModel(req dto.ContentType) (*model.ContentType, error) {
+	name, err := normalizeContentTypeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := model.ValueRules{
+		MaxLength: req.Rules.MaxLength,
+		Format:    req.Rules.Format,
+	}
+	for _, mt := range req.Rules.MimeTypes {
+		rules.MimeTypes = append(rules.MimeTypes, strings.ToLower(strings.TrimSpace(mt)))
+	}
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+
+	return &model.ContentType{Name: name, Rules: rules}, nil
+}
+
+func convertContentTypeModelToDTO(ct *model.ContentType) *dto.ContentType {
+	return &dto.ContentType{
+		ID:   ct.ID,
+		Name: ct.Name,
+		Rules: dto.ValueRules{
+			MaxLength: ct.Rules.MaxLength,
+			Format:    ct.Rules.Format,
+			MimeTypes: ct.Rules.MimeTypes,
+		},
+	}
+}
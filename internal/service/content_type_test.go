@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/model"
+	"reflect"
+	"testing"
+)
+
+func newContentTypeRepoMock() *MockRepository {
+	text := &model.ContentType{ID: 1, Name: "text"}
+	image := &model.ContentType{ID: 2, Name: "image"}
+	return &MockRepository{
+		MockedContent: []*model.Content{
+			{ID: 1, Details: []*model.Details{{ID: 1, ContentID: 1, ContentTypeID: 1, Value: "Test Value"}}},
+		},
+		ContentTypeNameToIDMap: map[string]*model.ContentType{"text": text, "image": image},
+		ContentTypeIDToNameMap: map[int]*model.ContentType{1: text, 2: image},
+	}
+}
+
+func TestService_GetContentTypes(t *testing.T) {
+	service := NewContentService(newContentTypeRepoMock(), testClock, nil)
+
+	contentTypes, err := service.GetContentTypes(context.Background())
+	if err != nil {
+		t.Fatalf("GetContentTypes() unexpected error = %v", err)
+	}
+
+	expected := []*dto.ContentType{{ID: 2, Name: "image"}, {ID: 1, Name: "text"}}
+	if !reflect.DeepEqual(contentTypes, expected) {
+		t.Errorf("GetContentTypes() got = %v, expected = %v", contentTypes, expected)
+	}
+}
+
+func TestService_CreateContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         dto.ContentType
+		expected    *dto.ContentType
+		expectedErr error
+	}{
+		{
+			name:     "successful create",
+			req:      dto.ContentType{Name: "quote"},
+			expected: &dto.ContentType{ID: 3, Name: "quote"},
+		},
+		{
+			name:     "name is normalized",
+			req:      dto.ContentType{Name: "  Code-Block "},
+			expected: &dto.ContentType{ID: 3, Name: "code-block"},
+		},
+		{
+			name:        "empty name",
+			req:         dto.ContentType{Name: " "},
+			expectedErr: ErrInvalidContentType,
+		},
+		{
+			name:        "malformed name",
+			req:         dto.ContentType{Name: "1 embed"},
+			expectedErr: ErrInvalidContentType,
+		},
+		{
+			name:        "duplicate name",
+			req:         dto.ContentType{Name: "Text"},
+			expectedErr: ErrContentTypeExists,
+		},
+		{
+			name:     "with value rules",
+			req:      dto.ContentType{Name: "embed", Rules: dto.ValueRules{Format: "url", MimeTypes: []string{" Video/* "}}},
+			expected: &dto.ContentType{ID: 3, Name: "embed", Rules: dto.ValueRules{Format: "url", MimeTypes: []string{"video/*"}}},
+		},
+		{
+			name:        "unknown format",
+			req:         dto.ContentType{Name: "embed", Rules: dto.ValueRules{Format: "html"}},
+			expectedErr: ErrInvalidContentType,
+		},
+		{
+			name:        "mime types without url format",
+			req:         dto.ContentType{Name: "embed", Rules: dto.ValueRules{MimeTypes: []string{"video/*"}}},
+			expectedErr: ErrInvalidContentType,
+		},
+		{
+			name:        "negative max length",
+			req:         dto.ContentType{Name: "code", Rules: dto.ValueRules{MaxLength: -1}},
+			expectedErr: ErrInvalidContentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(newContentTypeRepoMock(), testClock, nil)
+
+			contentType, err := service.CreateContentType(context.Background(), tt.req)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("CreateContentType() error = %v, expected error = %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(contentType, tt.expected) {
+				t.Errorf("CreateContentType() got = %v, expected = %v", contentType, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_UpdateContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		req         dto.ContentType
+		expected    *dto.ContentType
+		expectedErr error
+	}{
+		{
+			name:     "successful rename",
+			id:       2,
+			req:      dto.ContentType{Name: "picture"},
+			expected: &dto.ContentType{ID: 2, Name: "picture"},
+		},
+		{
+			name:        "content type not found",
+			id:          99,
+			req:         dto.ContentType{Name: "picture"},
+			expectedErr: ErrContentTypeNotFound,
+		},
+		{
+			name:        "name taken by another type",
+			id:          2,
+			req:         dto.ContentType{Name: "text"},
+			expectedErr: ErrContentTypeExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(newContentTypeRepoMock(), testClock, nil)
+
+			contentType, err := service.UpdateContentType(context.Background(), tt.id, tt.req)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("UpdateContentType() error = %v, expected error = %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(contentType, tt.expected) {
+				t.Errorf("UpdateContentType() got = %v, expected = %v", contentType, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_DeleteContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		expectedErr error
+	}{
+		{
+			name: "successful delete",
+			id:   2,
+		},
+		{
+			name:        "content type in use",
+			id:          1,
+			expectedErr: ErrContentTypeInUse,
+		},
+		{
+			name:        "content type not found",
+			id:          99,
+			expectedErr: ErrContentTypeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(newContentTypeRepoMock(), testClock, nil)
+
+			err := service.DeleteContentType(context.Background(), tt.id)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("DeleteContentType() error = %v, expected error = %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
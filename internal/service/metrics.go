@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"github.com/g-stro/content-management-service/internal/model"
+	"slices"
+)
+
+// noContentType labels business counters of content without details
+const noContentType = "none"
+
+// serviceMetrics counts content events. Its counters are nil, and discard updates, when metrics are disabled.
+type serviceMetrics struct {
+	created   *metrics.Counter
+	published *metrics.Counter
+}
+
+func newServiceMetrics(reg *metrics.Registry) serviceMetrics {
+	if reg == nil {
+		return serviceMetrics{}
+	}
+	return serviceMetrics{
+		created: reg.NewCounter("cms_content_created_total",
+			"Total number of content items created, by the content types of their details.", "content_type"),
+		published: reg.NewCounter("cms_content_published_total",
+			"Total number of content items published, manually or by schedule, by the content types of their details.",
+			"content_type"),
+	}
+}
+
+// countContent increments counter once for every distinct content type among the details of content
+func (s *Service) countContent(ctx context.Context, counter *metrics.Counter, content *model.Content) {
+	if counter == nil {
+		return
+	}
+	for _, name := range s.contentTypeLabels(ctx, content) {
+		counter.Inc(name)
+	}
+}
+
+// contentTypeLabels returns the sorted, distinct content type names of the details of content
+func (s *Service) contentTypeLabels(ctx context.Context, content *model.Content) []string {
+	var names []string
+	for _, d := range content.Details {
+		name := d.ContentTypeName
+		if name == "" {
+			// Metrics are best effort, so a failed lookup is not reported to the caller
+			if ct, err := s.contentTypes.lookupByID(ctx, d.ContentTypeID); err == nil && ct != nil {
+				name = ct.Name
+			}
+		}
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return []string{noContentType}
+	}
+	slices.Sort(names)
+	return names
+}
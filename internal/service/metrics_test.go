@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/metrics"
+	"github.com/g-stro/content-management-service/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_ContentMetrics(t *testing.T) {
+	now := fixedTime
+	clock := func() time.Time { return now }
+	reg := metrics.NewRegistry()
+	service := NewContentService(repository.NewInMemoryContentRepository(), clock, reg)
+	ctx := context.Background()
+
+	create := func(req dto.Content) *dto.Content {
+		t.Helper()
+		created, err := service.CreateContent(ctx, req, "editor")
+		if err != nil {
+			t.Fatalf("CreateContent() unexpected error: %v", err)
+		}
+		return created
+	}
+	transition := func(id int, statuses ...string) {
+		t.Helper()
+		for _, status := range statuses {
+			if _, err := service.TransitionContent(ctx, id, status, "editor", ""); err != nil {
+				t.Fatalf("TransitionContent() unexpected error: %v", err)
+			}
+		}
+	}
+
+	mixed := create(dto.Content{Name: "Mixed", Details: []dto.Details{{ContentType: "text", Value: "a"},
+		{ContentType: "image", Value: "https://example.com/a.png"}, {ContentType: "text", Value: "b"}}})
+	create(dto.Content{Name: "Empty"})
+	publishAt := now.Add(time.Minute)
+	scheduled := create(dto.Content{Name: "Scheduled", PublishAt: &publishAt,
+		Details: []dto.Details{{ContentType: "text", Value: "c"}}})
+
+	transition(mixed.ID, "review", "published")
+	transition(scheduled.ID, "review")
+	now = publishAt
+	if _, err := service.RunScheduledTransitions(ctx); err != nil {
+		t.Fatalf("RunScheduledTransitions() unexpected error: %v", err)
+	}
+
+	var b strings.Builder
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	for _, line := range []string{
+		`cms_content_created_total{content_type="image"} 1`,
+		`cms_content_created_total{content_type="none"} 1`,
+		`cms_content_created_total{content_type="text"} 2`,
+		`cms_content_published_total{content_type="image"} 1`,
+		`cms_content_published_total{content_type="text"} 2`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("WriteTo() got = \n%s\nexpected to contain %s", b.String(), line)
+		}
+	}
+}
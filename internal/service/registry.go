@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/repository"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"sync"
+	"time"
+)
+
+// contentTypeRegistryTTL is how long cached content types are used before they are reloaded, which bounds how long
+// changes made by other instances of the service go unnoticed
+const contentTypeRegistryTTL = time.Minute
+
+// contentTypeMissReloadInterval is how long after a load a lookup that misses reuses the cached content types instead
+// of reloading them, so that repeated lookups of a content type that does not exist cannot reload on every request
+const contentTypeMissReloadInterval = time.Second
+
+// contentTypeRegistry caches all content types so that converting content does not look up its types once per
+// detail. Content types are few and rarely change, so they are loaded together and reloaded once the cache expires,
+// after a content type is changed through the service, or when a lookup misses, as the content type may have been
+// created by another instance of the service since the cache was loaded. Misses shortly after a load do not reload.
+type contentTypeRegistry struct {
+	repo  repository.ContentRepository
+	clock clock
+
+	// reloadMu allows one reload at a time, so that concurrent lookups needing a reload share a single query
+	reloadMu sync.Mutex
+
+	mu     sync.RWMutex
+	loaded *loadedContentTypes
+}
+
+// loadedContentTypes holds the content types of one load. It is never modified, so it can be read without holding
+// the registry lock.
+type loadedContentTypes struct {
+	byID     map[int]*model.ContentType
+	byName   map[string]*model.ContentType
+	loadedAt time.Time
+}
+
+func newContentTypeRegistry(repo repository.ContentRepository, clock clock) *contentTypeRegistry {
+	return &contentTypeRegistry{repo: repo, clock: clock}
+}
+
+// lookupByID returns the content type with the ID, or nil if there is none
+func (r *contentTypeRegistry) lookupByID(ctx context.Context, id int) (*model.ContentType, error) {
+	loaded, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ct, ok := loaded.byID[id]; ok {
+		return ct, nil
+	}
+
+	loaded, err = r.reloadAfterMiss(ctx, loaded)
+	if err != nil {
+		return nil, err
+	}
+	return loaded.byID[id], nil
+}
+
+// lookupByName returns the content type with the name, or nil if there is none
+func (r *contentTypeRegistry) lookupByName(ctx context.Context, name string) (*model.ContentType, error) {
+	loaded, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ct, ok := loaded.byName[name]; ok {
+		return ct, nil
+	}
+
+	loaded, err = r.reloadAfterMiss(ctx, loaded)
+	if err != nil {
+		return nil, err
+	}
+	return loaded.byName[name], nil
+}
+
+// invalidate discards the cached content types so that the next lookup reloads them
+func (r *contentTypeRegistry) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loaded = nil
+}
+
+// cached returns the cached content types, or nil if there are none
+func (r *contentTypeRegistry) cached() *loadedContentTypes {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.loaded
+}
+
+// load returns the cached content types, reloading them from the repository when the cache is empty or expired
+func (r *contentTypeRegistry) load(ctx context.Context) (*loadedContentTypes, error) {
+	loaded := r.cached()
+	if loaded != nil && r.clock().Sub(loaded.loadedAt) < contentTypeRegistryTTL {
+		return loaded, nil
+	}
+	return r.reload(ctx, loaded)
+}
+
+// reloadAfterMiss reloads the content types in which a lookup missed, unless they were loaded too recently for a
+// reload to be worthwhile
+func (r *contentTypeRegistry) reloadAfterMiss(ctx context.Context,
+	loaded *loadedContentTypes) (*loadedContentTypes, error) {
+	if r.clock().Sub(loaded.loadedAt) < contentTypeMissReloadInterval {
+		return loaded, nil
+	}
+	return r.reload(ctx, loaded)
+}
+
+// reload replaces the stale content types with ones loaded from the repository. If another lookup has already
+// replaced them while this one waited, the content types it loaded are returned without querying again.
+func (r *contentTypeRegistry) reload(ctx context.Context, stale *loadedContentTypes) (*loadedContentTypes, error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	if loaded := r.cached(); loaded != nil && loaded != stale {
+		return loaded, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "contentTypeRegistry.load")
+	defer span.End()
+	contentTypes, err := r.repo.GetContentTypes(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to load content types", "error", err)
+		return nil, err
+	}
+
+	loaded := &loadedContentTypes{
+		byID:     make(map[int]*model.ContentType, len(contentTypes)),
+		byName:   make(map[string]*model.ContentType, len(contentTypes)),
+		loadedAt: r.clock(),
+	}
+	for _, ct := range contentTypes {
+		loaded.byID[ct.ID] = ct
+		loaded.byName[ct.Name] = ct
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loaded = loaded
+	return loaded, nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/repository"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRepository counts the repository calls that read content or content types
+type countingRepository struct {
+	repository.ContentRepository
+	calls atomic.Int64
+}
+
+func (r *countingRepository) ListContent(
+	ctx context.Context, filter repository.ContentFilter) ([]*model.Content, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.ListContent(ctx, filter)
+}
+
+func (r *countingRepository) GetContentByID(ctx context.Context, id int) (*model.Content, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.GetContentByID(ctx, id)
+}
+
+func (r *countingRepository) CreateContentWithDetails(
+	ctx context.Context, content *model.Content) (*model.Content, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.CreateContentWithDetails(ctx, content)
+}
+
+func (r *countingRepository) GetRevisions(ctx context.Context, contentID int) ([]*model.Revision, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.GetRevisions(ctx, contentID)
+}
+
+func (r *countingRepository) GetContentTypeByName(ctx context.Context, name string) (*model.ContentType, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.GetContentTypeByName(ctx, name)
+}
+
+func (r *countingRepository) GetContentTypeByID(ctx context.Context, id int) (*model.ContentType, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.GetContentTypeByID(ctx, id)
+}
+
+func (r *countingRepository) GetContentTypes(ctx context.Context) ([]*model.ContentType, error) {
+	r.calls.Add(1)
+	return r.ContentRepository.GetContentTypes(ctx)
+}
+
+// newCountingService returns a service over an in-memory repository holding items content with details each
+func newCountingService(t testing.TB, clock clock, items, details int) (*Service, *countingRepository) {
+	t.Helper()
+	repo := &countingRepository{ContentRepository: repository.NewInMemoryContentRepository()}
+	service := NewContentService(repo, clock, nil)
+
+	req := dto.Content{Name: "Test Name"}
+	for i := range details {
+		contentType := "text"
+		if i%2 == 1 {
+			contentType = "image"
+		}
+		req.Details = append(req.Details, dto.Details{ContentType: contentType, Value: "https://example.com/a.png"})
+	}
+	for i := range items {
+		req.Name = fmt.Sprintf("Test Name %d", i)
+		if _, err := service.CreateContent(context.Background(), req, "editor"); err != nil {
+			t.Fatalf("CreateContent() unexpected error: %v", err)
+		}
+	}
+	return service, repo
+}
+
+func TestService_RepositoryCallsAreConstant(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(s *Service) error
+		expected int64
+	}{
+		{
+			name: "list content",
+			call: func(s *Service) error {
+				_, err := s.GetContent(context.Background(), dto.ContentQuery{Limit: MaxPageSize})
+				return err
+			},
+			expected: 1,
+		},
+		{
+			name: "create content",
+			call: func(s *Service) error {
+				_, err := s.CreateContent(context.Background(), dto.Content{Name: "New", Details: []dto.Details{
+					{ContentType: "text", Value: "a"}, {ContentType: "text", Value: "b"}, {ContentType: "video",
+						Value: "https://example.com/a.mp4"}}}, "editor")
+				return err
+			},
+			expected: 2,
+		},
+		{
+			name: "revisions",
+			call: func(s *Service) error {
+				_, err := s.GetRevisions(context.Background(), 1)
+				return err
+			},
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, size := range []struct{ items, details int }{{1, 1}, {50, 5}} {
+			t.Run(fmt.Sprintf("%s/%d items with %d details", tt.name, size.items, size.details), func(t *testing.T) {
+				service, repo := newCountingService(t, testClock, size.items, size.details)
+
+				// Start with an empty cache, so that the content types are loaded once by the call
+				service.contentTypes.invalidate()
+				repo.calls.Store(0)
+				if err := tt.call(service); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := repo.calls.Load(); got != tt.expected {
+					t.Errorf("repository calls got = %d, expected = %d", got, tt.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestContentTypeRegistry(t *testing.T) {
+	now := fixedTime
+	clock := func() time.Time { return now }
+	service, repo := newCountingService(t, clock, 0, 0)
+	ctx := context.Background()
+
+	lookup := func(name string) *model.ContentType {
+		t.Helper()
+		ct, err := service.contentTypes.lookupByName(ctx, name)
+		if err != nil {
+			t.Fatalf("lookupByName() unexpected error: %v", err)
+		}
+		return ct
+	}
+
+	if ct := lookup("text"); ct == nil || ct.ID != 1 {
+		t.Fatalf("lookupByName() got = %v, expected the text content type", ct)
+	}
+	if got := repo.calls.Load(); got != 1 {
+		t.Errorf("repository calls got = %d, expected = 1", got)
+	}
+
+	// A miss reloads the content types once before reporting that there is none, and repeated misses shortly after
+	// the reload use the reloaded content types
+	now = now.Add(contentTypeMissReloadInterval)
+	for range 3 {
+		if ct := lookup("quote"); ct != nil {
+			t.Errorf("lookupByName() got = %v, expected = nil", ct)
+		}
+		if ct, err := service.contentTypes.lookupByID(ctx, 99); err != nil || ct != nil {
+			t.Errorf("lookupByID() got = %v, %v, expected = nil", ct, err)
+		}
+	}
+	if got := repo.calls.Load(); got != 2 {
+		t.Errorf("repository calls got = %d, expected = 2", got)
+	}
+
+	// Changing a content type through the service invalidates the cache
+	if _, err := service.CreateContentType(ctx, dto.ContentType{Name: "quote"}); err != nil {
+		t.Fatalf("CreateContentType() unexpected error: %v", err)
+	}
+	if ct := lookup("quote"); ct == nil {
+		t.Error("lookupByName() got = nil, expected the created content type")
+	}
+	if got := repo.calls.Load(); got != 3 {
+		t.Errorf("repository calls got = %d, expected = 3", got)
+	}
+
+	// A content type created by another instance is found on a miss, before the cache expires
+	now = now.Add(contentTypeMissReloadInterval)
+	created, err := repo.ContentRepository.CreateContentType(ctx, &model.ContentType{Name: "audio"})
+	if err != nil {
+		t.Fatalf("CreateContentType() unexpected error: %v", err)
+	}
+	if ct := lookup("audio"); ct == nil || ct.ID != created.ID {
+		t.Errorf("lookupByName() got = %v, expected the content type created by another instance", ct)
+	}
+	if ct, err := service.contentTypes.lookupByID(ctx, created.ID); err != nil || ct == nil {
+		t.Errorf("lookupByID() got = %v, %v, expected the content type created by another instance", ct, err)
+	}
+	if got := repo.calls.Load(); got != 4 {
+		t.Errorf("repository calls got = %d, expected = 4", got)
+	}
+
+	// Cached content types expire
+	lookup("text")
+	now = now.Add(contentTypeRegistryTTL)
+	lookup("text")
+	if got := repo.calls.Load(); got != 5 {
+		t.Errorf("repository calls got = %d, expected = 5", got)
+	}
+}
+
+func TestContentTypeRegistry_ReloadIsShared(t *testing.T) {
+	service, repo := newCountingService(t, testClock, 0, 0)
+	ctx := context.Background()
+	registry := service.contentTypes
+
+	stale, err := registry.load(ctx)
+	if err != nil {
+		t.Fatalf("load() unexpected error: %v", err)
+	}
+	repo.calls.Store(0)
+
+	// Lookups that missed in the same content types share the reload of whichever of them gets to reload first
+	first, err := registry.reload(ctx, stale)
+	if err != nil {
+		t.Fatalf("reload() unexpected error: %v", err)
+	}
+	second, err := registry.reload(ctx, stale)
+	if err != nil {
+		t.Fatalf("reload() unexpected error: %v", err)
+	}
+	if first == stale || second != first {
+		t.Errorf("reload() got = %p, %p, expected one new load shared by both from %p", first, second, stale)
+	}
+	if got := repo.calls.Load(); got != 1 {
+		t.Errorf("repository calls got = %d, expected = 1", got)
+	}
+}
+
+func BenchmarkService_GetContent(b *testing.B) {
+	for _, details := range []int{1, 5, 20} {
+		b.Run(fmt.Sprintf("%d items with %d details", MaxPageSize, details), func(b *testing.B) {
+			service, repo := newCountingService(b, testClock, MaxPageSize, details)
+			query := dto.ContentQuery{Limit: MaxPageSize}
+
+			repo.calls.Store(0)
+			b.ResetTimer()
+			for range b.N {
+				if _, err := service.GetContent(context.Background(), query); err != nil {
+					b.Fatalf("GetContent() unexpected error: %v", err)
+				}
+			}
+			b.ReportMetric(float64(repo.calls.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkService_CreateContent(b *testing.B) {
+	for _, details := range []int{1, 5, 20} {
+		b.Run(fmt.Sprintf("%d details", details), func(b *testing.B) {
+			service, repo := newCountingService(b, testClock, 0, 0)
+			req := dto.Content{Name: "Test Name"}
+			for range details {
+				req.Details = append(req.Details, dto.Details{ContentType: "text", Value: "test text"})
+			}
+
+			repo.calls.Store(0)
+			b.ResetTimer()
+			for range b.N {
+				if _, err := service.CreateContent(context.Background(), req, "editor"); err != nil {
+					b.Fatalf("CreateContent() unexpected error: %v", err)
+				}
+			}
+			b.ReportMetric(float64(repo.calls.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
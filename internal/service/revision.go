@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/apperror"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"time"
+)
+
+// GetRevisions returns the revision history of content, oldest first
+func (s *Service) GetRevisions(ctx context.Context, id int) ([]*dto.Revision, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetRevisions")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	revisions, err := s.repo.GetRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		// Distinguish content without history from content that does not exist
+		content, err := s.repo.GetContentByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			return nil, ErrContentNotFound
+		}
+	}
+
+	res := make([]*dto.Revision, 0)
+	for _, r := range revisions {
+		revisionDTO, err := s.convertRevisionModelToDTO(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, revisionDTO)
+	}
+
+	return res, nil
+}
+
+// GetRevision returns a single revision of content
+func (s *Service) GetRevision(ctx context.Context, id, revision int) (*dto.Revision, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetRevision")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	r, err := s.getRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertRevisionModelToDTO(ctx, r)
+}
+
+// DiffRevisions returns the field-level changes needed to go from one revision of content to another
+func (s *Service) DiffRevisions(ctx context.Context, id, from, to int) ([]dto.FieldChange, error) {
+	ctx, span := tracing.Start(ctx, "Service.DiffRevisions")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	fromRevision, err := s.GetRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.GetRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffContent(fromRevision.Content, toRevision.Content), nil
+}
+
+// RestoreRevision makes a past revision the current state of content, recording the restore as a new revision
+func (s *Service) RestoreRevision(ctx context.Context, id, revision int, author string) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.RestoreRevision")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	r, err := s.getRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetContentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrContentNotFound
+	}
+
+	content := &model.Content{
+		ID:               existing.ID,
+		Name:             r.Snapshot.Name,
+		Description:      r.Snapshot.Description,
+		CreationDate:     existing.CreationDate,
+		LastModifiedDate: s.clock(),
+		LastModifiedBy:   author,
+		PublishAt:        existing.PublishAt, // The publishing schedule is not part of the restored revision
+		UnpublishAt:      existing.UnpublishAt,
+	}
+	// The rules of content types may have changed since the revision, so its values are validated against the
+	// current rules like any other update
+	var fieldErrs []apperror.FieldError
+	for i, d := range r.Snapshot.Details {
+		ct, err := s.contentTypes.lookupByID(ctx, d.ContentTypeID)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to fetch content type", "error", err)
+			return nil, err
+		}
+		if ct == nil {
+			fieldErrs = append(fieldErrs, apperror.FieldError{
+				Field:   fmt.Sprintf("details[%d].content_type", i),
+				Message: fmt.Sprintf("unknown content type %d", d.ContentTypeID),
+			})
+			continue
+		}
+		if msg := validateValue(ct.Rules, d.Value); msg != "" {
+			fieldErrs = append(fieldErrs, apperror.FieldError{
+				Field:   fmt.Sprintf("details[%d].value", i),
+				Message: msg,
+			})
+		}
+		content.Details = append(content.Details, &model.Details{
+			ContentTypeID: d.ContentTypeID,
+			Value:         d.Value,
+		})
+	}
+	if len(fieldErrs) > 0 {
+		return nil, ErrValidation.WithFields(fieldErrs)
+	}
+
+	content, err = s.repo.UpdateContentWithDetails(ctx, content, nil)
+	if err != nil {
+		return nil, s.mapWriteError(err)
+	}
+
+	resp, err := s.convertContentModelToDTO(ctx, content)
+	if err != nil {
+		return nil, errors.New("failed to convert model to response DTO")
+	}
+
+	return resp, nil
+}
+
+// getRevision fetches a revision, translating a missing revision into ErrRevisionNotFound
+func (s *Service) getRevision(ctx context.Context, id, revision int) (*model.Revision, error) {
+	r, err := s.repo.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil || r.Snapshot == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return r, nil
+}
+
+func (s *Service) convertRevisionModelToDTO(ctx context.Context, revision *model.Revision) (*dto.Revision, error) {
+	content, err := s.convertContentModelToDTO(ctx, revision.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Revision{
+		Revision:  revision.Revision,
+		Author:    revision.Author,
+		CreatedAt: revision.CreatedAt,
+		Content:   content,
+	}, nil
+}
+
+// diffContent compares the editable fields, workflow status and publishing schedule of two content snapshots, so
+// that revisions written by transitions and by the scheduler show what they changed. Details are compared by position.
+func diffContent(from, to *dto.Content) []dto.FieldChange {
+	changes := make([]dto.FieldChange, 0)
+	compare := func(field string, a, b *string) {
+		if a == nil && b == nil {
+			return
+		}
+		if a != nil && b != nil && *a == *b {
+			return
+		}
+		changes = append(changes, dto.FieldChange{Field: field, From: a, To: b})
+	}
+
+	formatTime := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		s := t.Format(time.RFC3339Nano)
+		return &s
+	}
+
+	compare("name", &from.Name, &to.Name)
+	compare("description", &from.Description, &to.Description)
+	compare("status", &from.Status, &to.Status)
+	compare("publish_at", formatTime(from.PublishAt), formatTime(to.PublishAt))
+	compare("unpublish_at", formatTime(from.UnpublishAt), formatTime(to.UnpublishAt))
+
+	for i := 0; i < max(len(from.Details), len(to.Details)); i++ {
+		var fromType, fromValue, toType, toValue *string
+		if i < len(from.Details) {
+			fromType, fromValue = &from.Details[i].ContentType, &from.Details[i].Value
+		}
+		if i < len(to.Details) {
+			toType, toValue = &to.Details[i].ContentType, &to.Details[i].Value
+		}
+		compare(fmt.Sprintf("details[%d].content_type", i), fromType, toType)
+		compare(fmt.Sprintf("details[%d].value", i), fromValue, toValue)
+	}
+
+	return changes
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/apperror"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var revisionTime = time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func newRevisionRepoMock() *MockRepository {
+	return &MockRepository{
+		MockedContent: []*model.Content{
+			{ID: 1, Name: "Second Name", Description: "Test Description", CreationDate: revisionTime,
+				LastModifiedDate: revisionTime.Add(time.Hour),
+				Details:          []*model.Details{{ID: 2, ContentID: 1, ContentTypeID: 1, Value: "Second Value"}}},
+			{ID: 2, Name: "No History"},
+		},
+		Revisions: map[int][]*model.Revision{
+			1: {
+				{ID: 1, ContentID: 1, Revision: 1, Author: "alice", CreatedAt: revisionTime,
+					Snapshot: &model.Content{ID: 1, Name: "First Name", Description: "Test Description",
+						Details: []*model.Details{{ID: 1, ContentID: 1, ContentTypeID: 1, Value: "First Value"}}}},
+				{ID: 2, ContentID: 1, Revision: 2, Author: "bob", CreatedAt: revisionTime.Add(time.Hour),
+					Snapshot: &model.Content{ID: 1, Name: "Second Name", Description: "Test Description",
+						Details: []*model.Details{
+							{ID: 2, ContentID: 1, ContentTypeID: 1, Value: "Second Value"},
+							{ID: 3, ContentID: 1, ContentTypeID: 2, Value: "https://example.com/a.png"},
+						}}},
+			},
+		},
+		ContentTypeNameToIDMap: map[string]*model.ContentType{"text": {ID: 1, Name: "text"}},
+		ContentTypeIDToNameMap: map[int]*model.ContentType{
+			1: {ID: 1, Name: "text"},
+			2: {ID: 2, Name: "image"},
+		},
+	}
+}
+
+func TestService_GetRevisions(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            int
+		expectedCount int
+		expectedErr   error
+	}{
+		{name: "content with history", id: 1, expectedCount: 2},
+		{name: "content without history", id: 2, expectedCount: 0},
+		{name: "content not found", id: 3, expectedErr: ErrContentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(newRevisionRepoMock(), testClock, nil)
+
+			result, err := service.GetRevisions(context.Background(), tt.id)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("GetRevisions() error = %v, expected error = %v", err, tt.expectedErr)
+				return
+			}
+
+			if err == nil && len(result) != tt.expectedCount {
+				t.Errorf("GetRevisions() got %d revisions, expected = %d", len(result), tt.expectedCount)
+			}
+		})
+	}
+}
+
+func TestService_GetRevision(t *testing.T) {
+	service := NewContentService(newRevisionRepoMock(), testClock, nil)
+
+	result, err := service.GetRevision(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("GetRevision() unexpected error = %v", err)
+	}
+
+	expected := &dto.Revision{
+		Revision:  1,
+		Author:    "alice",
+		CreatedAt: revisionTime,
+		Content: &dto.Content{ID: 1, Name: "First Name", Description: "Test Description",
+			Details: []dto.Details{{ContentType: "text", Value: "First Value"}}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("GetRevision() got = %v, expected = %v", result, expected)
+	}
+
+	if _, err = service.GetRevision(context.Background(), 1, 3); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("GetRevision() error = %v, expected error = %v", err, ErrRevisionNotFound)
+	}
+}
+
+func TestService_DiffRevisions(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	service := NewContentService(newRevisionRepoMock(), testClock, nil)
+
+	result, err := service.DiffRevisions(context.Background(), 1, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffRevisions() unexpected error = %v", err)
+	}
+
+	expected := []dto.FieldChange{
+		{Field: "name", From: str("First Name"), To: str("Second Name")},
+		{Field: "details[0].value", From: str("First Value"), To: str("Second Value")},
+		{Field: "details[1].content_type", From: nil, To: str("image")},
+		{Field: "details[1].value", From: nil, To: str("https://example.com/a.png")},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("DiffRevisions() got = %+v, expected = %+v", result, expected)
+	}
+
+	result, err = service.DiffRevisions(context.Background(), 1, 2, 2)
+	if err != nil || len(result) != 0 {
+		t.Errorf("DiffRevisions() of identical revisions got = %+v, error = %v", result, err)
+	}
+
+	if _, err = service.DiffRevisions(context.Background(), 1, 1, 5); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("DiffRevisions() error = %v, expected error = %v", err, ErrRevisionNotFound)
+	}
+}
+
+func TestService_RestoreRevision(t *testing.T) {
+	repoMock := newRevisionRepoMock()
+	service := NewContentService(repoMock, testClock, nil)
+
+	result, err := service.RestoreRevision(context.Background(), 1, 1, "carol")
+	if err != nil {
+		t.Fatalf("RestoreRevision() unexpected error = %v", err)
+	}
+
+	expected := &dto.Content{
+		ID:               1,
+		Name:             "First Name",
+		Description:      "Test Description",
+		CreationDate:     revisionTime,
+		LastModifiedDate: fixedTime,
+		LastModifiedBy:   "carol",
+		Details:          []dto.Details{{ContentType: "text", Value: "First Value"}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("RestoreRevision() got = %v, expected = %v", result, expected)
+	}
+
+	if repoMock.UpdatedContent == nil || repoMock.UpdatedContent.Details[0].ID != 0 {
+		t.Errorf("RestoreRevision() should write fresh details, got = %+v", repoMock.UpdatedContent)
+	}
+
+	if _, err = service.RestoreRevision(context.Background(), 1, 9, "carol"); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("RestoreRevision() error = %v, expected error = %v", err, ErrRevisionNotFound)
+	}
+}
+
+func TestService_RestoreRevision_ValidatesAgainstCurrentRules(t *testing.T) {
+	repoMock := newRevisionRepoMock()
+	// The rules of the text type were tightened after the revision was written
+	text := &model.ContentType{ID: 1, Name: "text", Rules: model.ValueRules{MaxLength: 5}}
+	repoMock.ContentTypeNameToIDMap["text"] = text
+	repoMock.ContentTypeIDToNameMap[1] = text
+	service := NewContentService(repoMock, testClock, nil)
+
+	_, err := service.RestoreRevision(context.Background(), 1, 1, "carol")
+
+	var validationErr *apperror.Error
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("RestoreRevision() error = %v, expected a validation error", err)
+	}
+	if len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "details[0].value" {
+		t.Errorf("RestoreRevision() invalid fields got = %v, expected = [details[0].value]", validationErr.Fields)
+	}
+	if repoMock.UpdatedContent != nil {
+		t.Errorf("RestoreRevision() stored invalid content: %v", repoMock.UpdatedContent)
+	}
+}
+
+func TestService_DiffRevisions_WorkflowFields(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	repoMock := newRevisionRepoMock()
+	// A transition and a scheduled unpublish change nothing but the status and the schedule
+	second := repoMock.Revisions[1][1].Snapshot
+	second.Status = model.StatusDraft
+	inReview, published := *second, *second
+	inReview.Status = model.StatusReview
+	publishAt := revisionTime.Add(48 * time.Hour)
+	inReview.PublishAt = &publishAt
+	published.Status = model.StatusPublished
+	repoMock.Revisions[1] = append(repoMock.Revisions[1],
+		&model.Revision{ID: 3, ContentID: 1, Revision: 3, Author: "bob", CreatedAt: revisionTime.Add(2 * time.Hour),
+			Snapshot: &inReview},
+		&model.Revision{ID: 4, ContentID: 1, Revision: 4, Author: model.SchedulerAuthor,
+			CreatedAt: publishAt, Snapshot: &published})
+	service := NewContentService(repoMock, testClock, nil)
+
+	tests := []struct {
+		name     string
+		from, to int
+		expected []dto.FieldChange
+	}{
+		{name: "status and schedule", from: 2, to: 3, expected: []dto.FieldChange{
+			{Field: "status", From: str("draft"), To: str("review")},
+			{Field: "publish_at", From: nil, To: str("2024-06-03T00:00:00Z")},
+		}},
+		{name: "scheduled publish", from: 3, to: 4, expected: []dto.FieldChange{
+			{Field: "status", From: str("review"), To: str("published")},
+			{Field: "publish_at", From: str("2024-06-03T00:00:00Z"), To: nil},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.DiffRevisions(context.Background(), 1, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("DiffRevisions() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("DiffRevisions() got = %+v, expected = %+v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,66 +1,336 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/apperror"
 	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/mergepatch"
+	"github.com/g-stro/content-management-service/internal/metrics"
 	"github.com/g-stro/content-management-service/internal/model"
 	"github.com/g-stro/content-management-service/internal/repository"
-	"log/slog"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"strings"
 	"time"
 )
 
+var (
+	// ErrContentNotFound is returned when the requested content does not exist
+	ErrContentNotFound = apperror.NotFound("content_not_found", "content not found")
+	// ErrInvalidPatch is returned when a merge patch cannot be applied to the content
+	ErrInvalidPatch = apperror.Invalid("invalid_patch", "invalid merge patch")
+	// ErrInvalidQuery is returned when the pagination, sorting or filtering options are invalid
+	ErrInvalidQuery = apperror.Invalid("invalid_query", "invalid query")
+	// ErrRevisionNotFound is returned when the requested content revision does not exist
+	ErrRevisionNotFound = apperror.NotFound("revision_not_found", "revision not found")
+	// ErrInvalidStatus is returned when a workflow status is not recognised
+	ErrInvalidStatus = apperror.Invalid("invalid_status", "invalid status")
+	// ErrInvalidTransition is returned when content cannot move from its current status to the requested one
+	ErrInvalidTransition = apperror.Conflict("invalid_transition", "invalid status transition")
+	// ErrContentTypeNotFound is returned when the requested content type does not exist
+	ErrContentTypeNotFound = apperror.NotFound("content_type_not_found", "content type not found")
+	// ErrInvalidContentType is returned when a content type name or its rules are malformed
+	ErrInvalidContentType = apperror.Validation("invalid_content_type", "invalid content type")
+	// ErrContentTypeExists is returned when a content type with the same name already exists
+	ErrContentTypeExists = apperror.Conflict("content_type_exists", "content type already exists")
+	// ErrContentTypeInUse is returned when a content type is still referenced by content
+	ErrContentTypeInUse = apperror.Conflict("content_type_in_use", "content type is in use")
+	// ErrValidation is returned with the invalid fields when submitted content breaks the rules of its content types
+	ErrValidation = apperror.Validation("validation_failed", "validation failed")
+	// ErrInvalidDetailOrder is returned when a new order of content details does not list every detail exactly once
+	ErrInvalidDetailOrder = apperror.Invalid("invalid_detail_order", "order must list every detail position once")
+	// ErrPreconditionFailed is returned when content no longer matches the version the caller expected
+	ErrPreconditionFailed = apperror.PreconditionFailed("precondition_failed", "content has been modified")
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
 type clock func() time.Time
 
 type Service struct {
-	repo  repository.ContentRepository
-	clock clock
+	repo         repository.ContentRepository
+	clock        clock
+	contentTypes *contentTypeRegistry
+	metrics      serviceMetrics
 }
 
-func NewContentService(repo repository.ContentRepository, clock clock) *Service {
+// NewContentService returns a service storing content in repo. A nil clock uses the system time, and a nil registry
+// disables the content metrics.
+func NewContentService(repo repository.ContentRepository, clock clock, reg *metrics.Registry) *Service {
 	if clock == nil {
 		clock = time.Now // Default
 	}
 
 	return &Service{
-		repo:  repo,
-		clock: clock,
+		repo:         repo,
+		clock:        clock,
+		contentTypes: newContentTypeRegistry(repo, clock),
+		metrics:      newServiceMetrics(reg),
 	}
 }
 
-func (s *Service) GetContent() ([]*dto.Content, error) {
-	content, err := s.repo.GetAllContent()
+// GetContent returns a single page of content matching the query
+func (s *Service) GetContent(ctx context.Context, query dto.ContentQuery) (*dto.ContentPage, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetContent")
+	defer span.End()
+
+	filter, err := s.convertContentQueryToFilter(query)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(content) == 0 {
-		return []*dto.Content{}, nil
+	// Fetch one extra item to find out whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+
+	content, err := s.repo.ListContent(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.ContentPage{Content: []*dto.Content{}}
+	if len(content) > limit {
+		content = content[:limit]
+		last := content[limit-1]
+		page.NextCursor = repository.EncodeCursor(repository.Cursor{
+			SortBy:     filter.SortBy,
+			Descending: filter.Descending,
+			Value:      cursorValue(last, filter.SortBy),
+			ID:         last.ID,
+		})
 	}
 
-	res := make([]*dto.Content, 0)
 	for _, c := range content {
-		contentDTO, err := s.convertContentModelToDTO(c)
+		contentDTO, err := s.convertContentModelToDTO(ctx, c)
 		if err != nil {
 			return nil, err
 		}
-		res = append(res, contentDTO)
+		page.Content = append(page.Content, contentDTO)
 	}
+	span.SetAttribute("content.count", len(page.Content))
 
-	return res, nil
+	return page, nil
 }
 
-func (s *Service) CreateContent(req dto.Content) (*dto.Content, error) {
-	content, err := s.convertContentDTOToModel(&req)
+func (s *Service) GetContentByID(ctx context.Context, id int) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetContentByID")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	content, err := s.repo.GetContentByID(ctx, id)
 	if err != nil {
+		return nil, err
+	}
+
+	if content == nil {
+		return nil, ErrContentNotFound
+	}
+
+	return s.convertContentModelToDTO(ctx, content)
+}
+
+// ContentETag returns the entity tag of the current version of content, which changes whenever it is modified
+func ContentETag(content *dto.Content) string {
+	return contentETag(content.ID, content.LastModifiedDate)
+}
+
+func contentETag(id int, lastModified time.Time) string {
+	return fmt.Sprintf(`"%d-%d"`, id, lastModified.UnixNano())
+}
+
+// expectedVersion resolves the entity tags of an If-Match header value against content as it was read, returning
+// the last modification date a write must still find for the precondition to hold. The repository checks it
+// atomically with the write, so a concurrent change in between fails with ErrModified. It returns nil without a
+// header and for the wildcard "*", which matches any existing content, and ErrPreconditionFailed if no tag matches.
+func expectedVersion(content *model.Content, ifMatch string) (*time.Time, error) {
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	etag := contentETag(content.ID, content.LastModifiedDate)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		switch strings.TrimSpace(tag) {
+		case "*":
+			return nil, nil
+		case etag:
+			lastModified := content.LastModifiedDate
+			return &lastModified, nil
+		}
+	}
+
+	return nil, ErrPreconditionFailed
+}
+
+// CreateContent creates content with its details, attributing the first revision to the author
+func (s *Service) CreateContent(ctx context.Context, req dto.Content, author string) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.CreateContent")
+	defer span.End()
+
+	content, err := s.convertContentDTOToModel(ctx, &req)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			return nil, err
+		}
 		return nil, errors.New("failed to convert CreateRequestDTO to model")
 	}
+	content.LastModifiedBy = author
+	content.Status = model.StatusDraft // New content is never live until it has been published
+
+	content, err = s.repo.CreateContentWithDetails(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	s.countContent(ctx, s.metrics.created, content)
+
+	resp, err := s.convertContentModelToDTO(ctx, content)
+	if err != nil {
+		return nil, errors.New("failed to convert model to response DTO")
+	}
+
+	return resp, nil
+}
+
+// UpdateContent replaces the name, description and details of existing content. A non-empty ifMatch makes the update
+// conditional on the entity tag of the content.
+func (s *Service) UpdateContent(
+	ctx context.Context, id int, req dto.Content, author, ifMatch string) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.UpdateContent")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	existing, err := s.repo.GetContentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrContentNotFound
+	}
+
+	return s.replaceContent(ctx, existing, &req, author, ifMatch)
+}
+
+// PatchContent applies a JSON Merge Patch (RFC 7386) to existing content. A non-empty ifMatch makes the update
+// conditional on the entity tag of the content.
+func (s *Service) PatchContent(
+	ctx context.Context, id int, patch []byte, author, ifMatch string) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.PatchContent")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	existing, err := s.repo.GetContentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrContentNotFound
+	}
+
+	current, err := s.convertContentModelToDTO(ctx, existing)
+	if err != nil {
+		return nil, errors.New("failed to convert model to response DTO")
+	}
+
+	original, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := mergepatch.Apply(original, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var req dto.Content
+	if err = json.Unmarshal(patched, &req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return s.replaceContent(ctx, existing, &req, author, ifMatch)
+}
+
+// ReorderDetails moves the details of content into a new order. The order lists the current zero-based positions of
+// all details in the order they should appear, e.g. [2, 0, 1] moves the last of three details to the front. A
+// non-empty ifMatch makes the update conditional on the entity tag of the content.
+func (s *Service) ReorderDetails(
+	ctx context.Context, id int, order []int, author, ifMatch string) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.ReorderDetails")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	existing, err := s.repo.GetContentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrContentNotFound
+	}
+	expectedModified, err := expectedVersion(existing, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(order) != len(existing.Details) {
+		return nil, ErrInvalidDetailOrder
+	}
+	seen := make([]bool, len(order))
+	details := make([]*model.Details, 0, len(order))
+	for _, position := range order {
+		if position < 0 || position >= len(order) || seen[position] {
+			return nil, ErrInvalidDetailOrder
+		}
+		seen[position] = true
+		details = append(details, existing.Details[position])
+	}
+
+	content := *existing
+	content.Details = details
+	content.LastModifiedDate = s.clock()
+	content.LastModifiedBy = author
+
+	updated, err := s.repo.UpdateContentWithDetails(ctx, &content, expectedModified)
+	if err != nil {
+		return nil, s.mapWriteError(err)
+	}
+
+	resp, err := s.convertContentModelToDTO(ctx, updated)
+	if err != nil {
+		return nil, errors.New("failed to convert model to response DTO")
+	}
+
+	return resp, nil
+}
 
-	content, err = s.repo.CreateContentWithDetails(content)
+// replaceContent persists the requested state over the existing content, preserving its identity and creation date
+func (s *Service) replaceContent(
+	ctx context.Context, existing *model.Content, req *dto.Content, author, ifMatch string) (*dto.Content, error) {
+	expectedModified, err := expectedVersion(existing, ifMatch)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.convertContentModelToDTO(content)
+	content, err := s.convertContentDTOToModel(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			return nil, err
+		}
+		return nil, errors.New("failed to convert UpdateRequestDTO to model")
+	}
+	content.ID = existing.ID
+	content.CreationDate = existing.CreationDate
+	content.LastModifiedBy = author
+
+	content, err = s.repo.UpdateContentWithDetails(ctx, content, expectedModified)
+	if err != nil {
+		return nil, s.mapWriteError(err)
+	}
+
+	resp, err := s.convertContentModelToDTO(ctx, content)
 	if err != nil {
 		return nil, errors.New("failed to convert model to response DTO")
 	}
@@ -68,30 +338,227 @@ func (s *Service) CreateContent(req dto.Content) (*dto.Content, error) {
 	return resp, nil
 }
 
-// convertContentTypeNameToID converts a content type name string to content type ID integer
-func (s *Service) convertContentTypeNameToID(name string) (int, error) {
-	ct, err := s.repo.GetContentTypeByName(name)
+// SearchContent returns the content most relevant to a full-text search query. Unless includeUnpublished is set,
+// only published content is searched.
+func (s *Service) SearchContent(
+	ctx context.Context, query string, limit int, includeUnpublished bool) ([]*dto.SearchResult, error) {
+	ctx, span := tracing.Start(ctx, "Service.SearchContent")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: search query must not be empty", ErrInvalidQuery)
+	}
+
+	switch {
+	case limit == 0:
+		limit = DefaultPageSize
+	case limit < 0 || limit > MaxPageSize:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxPageSize)
+	}
+
+	status := model.StatusPublished
+	if includeUnpublished {
+		status = "" // Any status
+	}
+
+	results, err := s.repo.SearchContent(ctx, query, limit, status)
 	if err != nil {
-		slog.Error("failed to fetch ContentTypeID", "error", err)
-		return 0, err
+		return nil, err
+	}
+
+	res := make([]*dto.SearchResult, 0)
+	for _, r := range results {
+		contentDTO, err := s.convertContentModelToDTO(ctx, r.Content)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &dto.SearchResult{
+			Content: contentDTO,
+			Score:   r.Rank,
+			Snippet: r.Snippet,
+		})
+	}
+
+	return res, nil
+}
+
+// DeleteContent soft-deletes content, moving it to the trash. A non-empty ifMatch makes the deletion conditional on
+// the entity tag of the content.
+func (s *Service) DeleteContent(ctx context.Context, id int, ifMatch string) error {
+	ctx, span := tracing.Start(ctx, "Service.DeleteContent")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	expectedModified, err := s.expectedVersionOf(ctx, id, ifMatch)
+	if err != nil {
+		return err
+	}
+
+	return s.mapWriteError(s.repo.SoftDeleteContent(ctx, id, s.clock(), expectedModified))
+}
+
+// GetDeletedContent returns all content currently in the trash
+func (s *Service) GetDeletedContent(ctx context.Context) ([]*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.GetDeletedContent")
+	defer span.End()
+
+	content, err := s.repo.GetDeletedContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.Content, 0)
+	for _, c := range content {
+		contentDTO, err := s.convertContentModelToDTO(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, contentDTO)
+	}
+
+	return res, nil
+}
+
+// RestoreContent moves soft-deleted content out of the trash
+func (s *Service) RestoreContent(ctx context.Context, id int) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.RestoreContent")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	if err := s.mapWriteError(s.repo.RestoreContent(ctx, id)); err != nil {
+		return nil, err
+	}
+
+	return s.GetContentByID(ctx, id)
+}
+
+// PurgeContent permanently removes content and its details, whether or not it is in the trash. A non-empty ifMatch
+// makes the deletion conditional on the entity tag of the content, which must then not be in the trash.
+func (s *Service) PurgeContent(ctx context.Context, id int, ifMatch string) error {
+	ctx, span := tracing.Start(ctx, "Service.PurgeContent")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	expectedModified, err := s.expectedVersionOf(ctx, id, ifMatch)
+	if err != nil {
+		return err
+	}
+
+	return s.mapWriteError(s.repo.PurgeContent(ctx, id, expectedModified))
+}
+
+// expectedVersionOf reads content to resolve a non-empty If-Match header value, see expectedVersion
+func (s *Service) expectedVersionOf(ctx context.Context, id int, ifMatch string) (*time.Time, error) {
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	existing, err := s.repo.GetContentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrContentNotFound
+	}
+	return expectedVersion(existing, ifMatch)
+}
+
+// mapWriteError translates repository not found errors into ErrContentNotFound, and failed conditional writes into
+// ErrPreconditionFailed
+func (s *Service) mapWriteError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrContentNotFound
+	case errors.Is(err, repository.ErrModified):
+		return ErrPreconditionFailed
+	}
+	return err
+}
+
+// convertContentQueryToFilter validates the query and applies the default page size and ordering
+func (s *Service) convertContentQueryToFilter(query dto.ContentQuery) (repository.ContentFilter, error) {
+	filter := repository.ContentFilter{
+		Limit:         query.Limit,
+		SortBy:        repository.SortField(query.SortBy),
+		Descending:    query.Descending,
+		NamePrefix:    query.NamePrefix,
+		ContentType:   query.ContentType,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+	}
+
+	// Only published content is listed unless another status is requested explicitly
+	switch query.Status {
+	case "":
+		filter.Status = model.StatusPublished
+	case StatusAny:
+		filter.Status = ""
+	default:
+		status, err := parseStatus(query.Status)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		filter.Status = status
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultPageSize
+	case filter.Limit < 0 || filter.Limit > MaxPageSize:
+		return filter, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxPageSize)
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = repository.SortByCreationDate
+	}
+	if !filter.SortBy.Valid() {
+		return filter, fmt.Errorf("%w: unsupported sort field %q", ErrInvalidQuery, query.SortBy)
+	}
+
+	if query.Cursor != "" {
+		cursor, err := repository.DecodeCursor(query.Cursor)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		if cursor.SortBy != filter.SortBy || cursor.Descending != filter.Descending {
+			return filter, fmt.Errorf("%w: cursor does not match the requested sort order", ErrInvalidQuery)
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// cursorValue returns the value of the sort field used to position a cursor after the content
+func cursorValue(content *model.Content, sortBy repository.SortField) string {
+	switch sortBy {
+	case repository.SortByName:
+		return content.Name
+	case repository.SortByLastModifiedDate:
+		return content.LastModifiedDate.Format(time.RFC3339Nano)
+	default:
+		return content.CreationDate.Format(time.RFC3339Nano)
 	}
-	return ct.ID, nil
 }
 
 // convertContentTypeIDToName converts a content type ID integer to content type string
-func (s *Service) convertContentTypeIDToName(id int) (string, error) {
-	ct, err := s.repo.GetContentTypeByID(id)
+func (s *Service) convertContentTypeIDToName(ctx context.Context, id int) (string, error) {
+	ct, err := s.contentTypes.lookupByID(ctx, id)
 	if err != nil {
-		slog.Error("failed to fetch ContentTypeName", "error", err)
+		logging.FromContext(ctx).Error("failed to fetch ContentTypeName", "error", err)
 		return "", err
 	}
+	if ct == nil {
+		return "", fmt.Errorf("content type %d does not exist", id)
+	}
 	return ct.Name, nil
 }
 
-func (s *Service) convertContentDTOToModel(content *dto.Content) (*model.Content, error) {
+func (s *Service) convertContentDTOToModel(ctx context.Context, content *dto.Content) (*model.Content, error) {
 	if content == nil {
 		err := errors.New("request DTO is nil")
-		slog.Error("request DTO is nil", "error", err)
+		logging.FromContext(ctx).Error("request DTO is nil", "error", err)
 		return nil, err
 	}
 
@@ -101,48 +568,84 @@ func (s *Service) convertContentDTOToModel(content *dto.Content) (*model.Content
 		Description:      content.Description,
 		CreationDate:     currTime,
 		LastModifiedDate: currTime,
+		PublishAt:        content.PublishAt,
+		UnpublishAt:      content.UnpublishAt,
+	}
+
+	var fieldErrs []apperror.FieldError
+	if content.PublishAt != nil && content.UnpublishAt != nil && !content.UnpublishAt.After(*content.PublishAt) {
+		fieldErrs = append(fieldErrs, apperror.FieldError{Field: "unpublish_at", Message: "must be after publish_at"})
 	}
 
-	// Convert the res details
+	// Convert the res details, validating each value against the rules of its content type
 	if content.Details != nil {
-		for _, d := range content.Details {
-			contentTypeID, err := s.convertContentTypeNameToID(d.ContentType)
+		for i, d := range content.Details {
+			ct, err := s.contentTypes.lookupByName(ctx, d.ContentType)
 			if err != nil {
-				slog.Error("failed to convert res type to ID", "error", err)
+				logging.FromContext(ctx).Error("failed to fetch ContentTypeID", "error", err)
 				return nil, err
 			}
+			if ct == nil {
+				fieldErrs = append(fieldErrs, apperror.FieldError{
+					Field:   fmt.Sprintf("details[%d].content_type", i),
+					Message: fmt.Sprintf("unknown content type %q", d.ContentType),
+				})
+				continue
+			}
+			if msg := validateValue(ct.Rules, d.Value); msg != "" {
+				fieldErrs = append(fieldErrs, apperror.FieldError{
+					Field:   fmt.Sprintf("details[%d].value", i),
+					Message: msg,
+				})
+			}
 			detail := model.Details{
-				ContentTypeID: contentTypeID,
+				ContentTypeID: ct.ID,
 				Value:         d.Value,
 			}
 			res.Details = append(res.Details, &detail)
 		}
 	}
 
+	if len(fieldErrs) > 0 {
+		return nil, ErrValidation.WithFields(fieldErrs)
+	}
+
 	return res, nil
 }
 
-func (s *Service) convertContentModelToDTO(content *model.Content) (*dto.Content, error) {
+func (s *Service) convertContentModelToDTO(ctx context.Context, content *model.Content) (*dto.Content, error) {
 	if content == nil {
 		err := errors.New("content is nil")
-		slog.Error("content is nil", "error", err)
+		logging.FromContext(ctx).Error("content is nil", "error", err)
 		return nil, err
 	}
 
 	res := &dto.Content{
-		ID:           content.ID,
-		Name:         content.Name,
-		CreationDate: content.CreationDate,
-		Description:  content.Description,
+		ID:               content.ID,
+		Name:             content.Name,
+		CreationDate:     content.CreationDate,
+		LastModifiedDate: content.LastModifiedDate,
+		LastModifiedBy:   content.LastModifiedBy,
+		PublishAt:        content.PublishAt,
+		UnpublishAt:      content.UnpublishAt,
+		DeletedAt:        content.DeletedAt,
+		Description:      content.Description,
+		Status:           string(content.Status),
 	}
 
 	// Convert the content details
 	if content.Details != nil {
 		for _, d := range content.Details {
-			contentType, err := s.convertContentTypeIDToName(d.ContentTypeID)
-			if err != nil {
-				slog.Error("failed to convert ID to content type", "error", err)
-				return nil, err
+			// Details read from the repository carry their joined type name; others, such as revision snapshots,
+			// are resolved from the registry
+			contentType := d.ContentTypeName
+			if contentType == "" {
+				var err error
+				contentType, err = s.convertContentTypeIDToName(ctx, d.ContentTypeID)
+				if err != nil {
+					logging.FromContext(ctx).Error("failed to convert ID to content type", "error", err)
+					return nil, err
+				}
 			}
 			detail := dto.Details{
 				ContentType: contentType,
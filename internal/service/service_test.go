@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"github.com/g-stro/content-management-service/internal/dto"
 	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/repository"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -15,22 +19,77 @@ var testClock = func() time.Time {
 }
 
 type MockRepository struct {
-	MockedContent  []*model.Content
-	MockedError    error
-	CreatedContent *model.Content
+	MockedContent    []*model.Content
+	MockedError      error
+	CreatedContent   *model.Content
+	UpdatedContent   *model.Content
+	LastFilter       repository.ContentFilter
+	SearchResults    []*model.SearchResult
+	Revisions        map[int][]*model.Revision
+	LastSearch       string
+	LastSearchStatus model.ContentStatus
 
 	ContentTypeNameToIDMap map[string]*model.ContentType
 	ContentTypeIDToNameMap map[int]*model.ContentType
 }
 
-func (m *MockRepository) GetAllContent() ([]*model.Content, error) {
+func (m *MockRepository) GetAllContent(ctx context.Context) ([]*model.Content, error) {
 	if m.MockedError != nil {
 		return nil, m.MockedError
 	}
 	return m.MockedContent, nil
 }
 
-func (m *MockRepository) CreateContentWithDetails(content *model.Content) (*model.Content, error) {
+func (m *MockRepository) ListContent(ctx context.Context, filter repository.ContentFilter) ([]*model.Content, error) {
+	m.LastFilter = filter
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	if len(m.MockedContent) > filter.Limit {
+		return m.MockedContent[:filter.Limit], nil
+	}
+	return m.MockedContent, nil
+}
+
+func (m *MockRepository) SearchContent(
+	ctx context.Context, query string, limit int, status model.ContentStatus) ([]*model.SearchResult, error) {
+	m.LastSearch = query
+	m.LastSearchStatus = status
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	if len(m.SearchResults) > limit {
+		return m.SearchResults[:limit], nil
+	}
+	return m.SearchResults, nil
+}
+
+func (m *MockRepository) GetContentByID(ctx context.Context, id int) (*model.Content, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	for _, c := range m.MockedContent {
+		if c.ID == id && c.DeletedAt == nil {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetDeletedContent(ctx context.Context) ([]*model.Content, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	deleted := make([]*model.Content, 0)
+	for _, c := range m.MockedContent {
+		if c.DeletedAt != nil {
+			deleted = append(deleted, c)
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockRepository) CreateContentWithDetails(ctx context.Context, content *model.Content) (*model.Content, error) {
 	if m.MockedError != nil {
 		return nil, m.MockedError
 	}
@@ -39,33 +98,219 @@ func (m *MockRepository) CreateContentWithDetails(content *model.Content) (*mode
 	return content, nil
 }
 
-func (m *MockRepository) GetContentTypeByName(name string) (*model.ContentType, error) {
+func (m *MockRepository) UpdateContentWithDetails(
+	ctx context.Context, content *model.Content, expectedModified *time.Time) (*model.Content, error) {
 	if m.MockedError != nil {
 		return nil, m.MockedError
 	}
-	val, ok := m.ContentTypeNameToIDMap[name]
-	if !ok {
-		return nil, errors.New("content type not found")
+	for _, c := range m.MockedContent {
+		if c.ID == content.ID && c.DeletedAt == nil {
+			if expectedModified != nil && !c.LastModifiedDate.Equal(*expectedModified) {
+				return nil, repository.ErrModified
+			}
+			m.UpdatedContent = content
+			return content, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *MockRepository) UpdateContentStatus(ctx context.Context, id int, from, to model.ContentStatus,
+	modifiedAt time.Time, author string, expectedModified *time.Time) (*model.Content, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	for _, c := range m.MockedContent {
+		if c.ID == id && c.Status == from && c.DeletedAt == nil {
+			if expectedModified != nil && !c.LastModifiedDate.Equal(*expectedModified) {
+				return nil, repository.ErrModified
+			}
+			c.Status = to
+			c.LastModifiedDate = modifiedAt
+			c.LastModifiedBy = author
+			return c, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *MockRepository) ApplyScheduledTransitions(
+	ctx context.Context, now time.Time, limit int) ([]*model.Content, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	transitioned := make([]*model.Content, 0)
+	for _, c := range m.MockedContent {
+		if len(transitioned) == limit {
+			break
+		}
+		switch {
+		case c.Status == model.StatusReview && c.PublishAt != nil && !c.PublishAt.After(now):
+			c.Status, c.PublishAt = model.StatusPublished, nil
+		case c.Status == model.StatusPublished && c.UnpublishAt != nil && !c.UnpublishAt.After(now):
+			c.Status, c.UnpublishAt = model.StatusArchived, nil
+		default:
+			continue
+		}
+		c.LastModifiedDate, c.LastModifiedBy = now, model.SchedulerAuthor
+		transitioned = append(transitioned, c)
+	}
+	return transitioned, nil
+}
+
+func (m *MockRepository) SoftDeleteContent(
+	ctx context.Context, id int, deletedAt time.Time, expectedModified *time.Time) error {
+	if m.MockedError != nil {
+		return m.MockedError
+	}
+	for _, c := range m.MockedContent {
+		if c.ID == id && c.DeletedAt == nil {
+			if expectedModified != nil && !c.LastModifiedDate.Equal(*expectedModified) {
+				return repository.ErrModified
+			}
+			c.DeletedAt = &deletedAt
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (m *MockRepository) RestoreContent(ctx context.Context, id int) error {
+	if m.MockedError != nil {
+		return m.MockedError
+	}
+	for _, c := range m.MockedContent {
+		if c.ID == id && c.DeletedAt != nil {
+			c.DeletedAt = nil
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (m *MockRepository) PurgeContent(ctx context.Context, id int, expectedModified *time.Time) error {
+	if m.MockedError != nil {
+		return m.MockedError
+	}
+	for i, c := range m.MockedContent {
+		if c.ID == id {
+			if expectedModified != nil && !c.LastModifiedDate.Equal(*expectedModified) {
+				return repository.ErrModified
+			}
+			m.MockedContent = append(m.MockedContent[:i], m.MockedContent[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (m *MockRepository) GetRevisions(ctx context.Context, contentID int) ([]*model.Revision, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	return m.Revisions[contentID], nil
+}
+
+func (m *MockRepository) GetRevision(ctx context.Context, contentID, revision int) (*model.Revision, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	for _, r := range m.Revisions[contentID] {
+		if r.Revision == revision {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetContentTypeByName(ctx context.Context, name string) (*model.ContentType, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	// Unknown names are not an error, matching the repository
+	return m.ContentTypeNameToIDMap[name], nil
+}
+
+func (m *MockRepository) GetContentTypeByID(ctx context.Context, id int) (*model.ContentType, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
+	}
+	// Unknown IDs are not an error, matching the repository
+	return m.ContentTypeIDToNameMap[id], nil
+}
+
+func (m *MockRepository) GetContentTypes(ctx context.Context) ([]*model.ContentType, error) {
+	if m.MockedError != nil {
+		return nil, m.MockedError
 	}
-	return val, nil
+	contentTypes := make([]*model.ContentType, 0)
+	for _, ct := range m.ContentTypeIDToNameMap {
+		contentTypes = append(contentTypes, ct)
+	}
+	slices.SortFunc(contentTypes, func(a, b *model.ContentType) int { return strings.Compare(a.Name, b.Name) })
+	return contentTypes, nil
 }
 
-func (m *MockRepository) GetContentTypeByID(id int) (*model.ContentType, error) {
+func (m *MockRepository) CreateContentType(
+	ctx context.Context, contentType *model.ContentType) (*model.ContentType, error) {
 	if m.MockedError != nil {
 		return nil, m.MockedError
 	}
-	val, ok := m.ContentTypeIDToNameMap[id]
+	if _, ok := m.ContentTypeNameToIDMap[contentType.Name]; ok {
+		return nil, repository.ErrDuplicate
+	}
+	for id := range m.ContentTypeIDToNameMap {
+		contentType.ID = max(contentType.ID, id)
+	}
+	contentType.ID++
+	m.ContentTypeIDToNameMap[contentType.ID] = contentType
+	m.ContentTypeNameToIDMap[contentType.Name] = contentType
+	return contentType, nil
+}
+
+func (m *MockRepository) UpdateContentType(ctx context.Context, contentType *model.ContentType) error {
+	if m.MockedError != nil {
+		return m.MockedError
+	}
+	existing, ok := m.ContentTypeIDToNameMap[contentType.ID]
 	if !ok {
-		return nil, errors.New("content type not found")
+		return repository.ErrNotFound
 	}
-	return val, nil
+	if other, ok := m.ContentTypeNameToIDMap[contentType.Name]; ok && other.ID != contentType.ID {
+		return repository.ErrDuplicate
+	}
+	delete(m.ContentTypeNameToIDMap, existing.Name)
+	m.ContentTypeIDToNameMap[contentType.ID] = contentType
+	m.ContentTypeNameToIDMap[contentType.Name] = contentType
+	return nil
+}
+
+func (m *MockRepository) DeleteContentType(ctx context.Context, id int) error {
+	if m.MockedError != nil {
+		return m.MockedError
+	}
+	existing, ok := m.ContentTypeIDToNameMap[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	for _, c := range m.MockedContent {
+		for _, d := range c.Details {
+			if d.ContentTypeID == id {
+				return repository.ErrInUse
+			}
+		}
+	}
+	delete(m.ContentTypeIDToNameMap, id)
+	delete(m.ContentTypeNameToIDMap, existing.Name)
+	return nil
 }
 
 func TestService_GetContent(t *testing.T) {
 	tests := []struct {
 		name      string
+		query     dto.ContentQuery
 		repoMock  *MockRepository
-		expected  []*dto.Content
+		expected  *dto.ContentPage
 		expectErr bool
 	}{
 		{
@@ -80,24 +325,87 @@ func TestService_GetContent(t *testing.T) {
 					},
 				},
 			},
-			expected: []*dto.Content{
-				{
-					ID:          1,
-					Name:        "Test Name",
-					Description: "Test Description",
-					Details:     nil,
+			expected: &dto.ContentPage{
+				Content: []*dto.Content{
+					{
+						ID:          1,
+						Name:        "Test Name",
+						Description: "Test Description",
+						Details:     nil,
+					},
 				},
 			},
 			expectErr: false,
 		},
+		{
+			name: "next cursor when more content follows",
+			query: dto.ContentQuery{
+				Limit:  1,
+				SortBy: "name",
+			},
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{
+					{ID: 1, Name: "A"},
+					{ID: 2, Name: "B"},
+				},
+			},
+			expected: &dto.ContentPage{
+				Content: []*dto.Content{{ID: 1, Name: "A"}},
+				NextCursor: repository.EncodeCursor(repository.Cursor{
+					SortBy: repository.SortByName, Value: "A", ID: 1}),
+			},
+			expectErr: false,
+		},
 		{
 			name: "no content available",
 			repoMock: &MockRepository{
 				MockedContent: []*model.Content{},
 			},
-			expected:  []*dto.Content{},
+			expected:  &dto.ContentPage{Content: []*dto.Content{}},
 			expectErr: false,
 		},
+		{
+			name:      "invalid sort field",
+			query:     dto.ContentQuery{SortBy: "id"},
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name:      "limit too large",
+			query:     dto.ContentQuery{Limit: MaxPageSize + 1},
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name: "cursor from a different sort order",
+			query: dto.ContentQuery{
+				SortBy: "name",
+				Cursor: repository.EncodeCursor(repository.Cursor{
+					SortBy: repository.SortByCreationDate, Value: "2025-01-01T00:00:00Z", ID: 1}),
+			},
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name: "cursor with a sort value that is not a date",
+			query: dto.ContentQuery{
+				Cursor: repository.EncodeCursor(repository.Cursor{
+					SortBy: repository.SortByCreationDate, Value: "alpha", ID: 1}),
+			},
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name:      "malformed cursor",
+			query:     dto.ContentQuery{Cursor: "not-a-cursor"},
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
 		{
 			name: "error while fetching content",
 			repoMock: &MockRepository{
@@ -110,9 +418,9 @@ func TestService_GetContent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewContentService(tt.repoMock, testClock)
+			service := NewContentService(tt.repoMock, testClock, nil)
 
-			result, err := service.GetContent()
+			result, err := service.GetContent(context.Background(), tt.query)
 
 			if (err != nil) != tt.expectErr {
 				t.Errorf("GetContent() error = %v, expectErr = %v", err, tt.expectErr)
@@ -126,6 +434,210 @@ func TestService_GetContent(t *testing.T) {
 	}
 }
 
+func TestService_GetContent_Filter(t *testing.T) {
+	createdAfter := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	cursor := repository.Cursor{SortBy: repository.SortByLastModifiedDate, Descending: true,
+		Value: "2025-01-01T00:00:00Z", ID: 7}
+
+	repoMock := &MockRepository{}
+	service := NewContentService(repoMock, testClock, nil)
+
+	_, err := service.GetContent(context.Background(), dto.ContentQuery{
+		Limit:        10,
+		Cursor:       repository.EncodeCursor(cursor),
+		SortBy:       "last_modified_date",
+		Descending:   true,
+		NamePrefix:   "Test",
+		ContentType:  "image",
+		CreatedAfter: &createdAfter,
+	})
+	if err != nil {
+		t.Fatalf("GetContent() unexpected error = %v", err)
+	}
+
+	expected := repository.ContentFilter{
+		Limit:        11, // One extra item to detect the next page
+		Status:       model.StatusPublished,
+		SortBy:       repository.SortByLastModifiedDate,
+		Descending:   true,
+		Cursor:       &cursor,
+		NamePrefix:   "Test",
+		ContentType:  "image",
+		CreatedAfter: &createdAfter,
+	}
+	if !reflect.DeepEqual(repoMock.LastFilter, expected) {
+		t.Errorf("GetContent() filter got = %+v, expected = %+v", repoMock.LastFilter, expected)
+	}
+}
+
+func TestService_GetContentByID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		repoMock    *MockRepository
+		expected    *dto.Content
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "successful fetch",
+			id:   1,
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{
+					{
+						ID:          1,
+						Name:        "Test Name",
+						Description: "Test Description",
+						Details:     []*model.Details{{ID: 1, ContentID: 1, ContentTypeID: 1, Value: "Test Value"}},
+					},
+				},
+				ContentTypeIDToNameMap: map[int]*model.ContentType{1: {ID: 1, Name: "text"}},
+			},
+			expected: &dto.Content{
+				ID:          1,
+				Name:        "Test Name",
+				Description: "Test Description",
+				Details:     []dto.Details{{ContentType: "text", Value: "Test Value"}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "content not found",
+			id:   2,
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{{ID: 1, Name: "Test Name"}},
+			},
+			expected:    nil,
+			expectedErr: ErrContentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "error while fetching content",
+			id:   1,
+			repoMock: &MockRepository{
+				MockedError: errors.New("repository error"),
+			},
+			expected:  nil,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(tt.repoMock, testClock, nil)
+
+			result, err := service.GetContentByID(context.Background(), tt.id)
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("GetContentByID() error = %v, expectErr = %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("GetContentByID() error = %v, expected error = %v", err, tt.expectedErr)
+				return
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("GetContentByID() got = %v, expected = %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_DeleteContent_IfMatch(t *testing.T) {
+	etag := ContentETag(&dto.Content{ID: 1, LastModifiedDate: fixedTime})
+
+	tests := []struct {
+		name        string
+		id          int
+		ifMatch     string
+		expectedErr error
+	}{
+		{name: "matching entity tag", id: 1, ifMatch: etag},
+		{name: "one of several entity tags", id: 1, ifMatch: `"1-0", ` + etag},
+		{name: "wildcard", id: 1, ifMatch: "*"},
+		{name: "stale entity tag", id: 1, ifMatch: `"1-0"`, expectedErr: ErrPreconditionFailed},
+		{name: "weak entity tag", id: 1, ifMatch: "W/" + etag, expectedErr: ErrPreconditionFailed},
+		{name: "content not found", id: 2, ifMatch: "*", expectedErr: ErrContentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := &model.Content{ID: 1, Name: "Test Name", CreationDate: fixedTime, LastModifiedDate: fixedTime}
+			service := NewContentService(&MockRepository{MockedContent: []*model.Content{content}}, testClock, nil)
+
+			err := service.DeleteContent(context.Background(), tt.id, tt.ifMatch)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("DeleteContent() error = %v, expected error = %v", err, tt.expectedErr)
+			}
+			if deleted := content.DeletedAt != nil; deleted != (tt.expectedErr == nil) {
+				t.Errorf("DeleteContent() deleted = %v, expected = %v", deleted, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+// staleReadRepository returns content as it was before a concurrent modification, which the write then detects
+type staleReadRepository struct {
+	*MockRepository
+	stale *model.Content
+}
+
+func (r *staleReadRepository) GetContentByID(ctx context.Context, id int) (*model.Content, error) {
+	return r.stale, nil
+}
+
+func TestService_ConditionalWritesDetectConcurrentModification(t *testing.T) {
+	stale := &model.Content{ID: 1, Name: "Test Name", Status: model.StatusDraft, CreationDate: fixedTime,
+		LastModifiedDate: fixedTime}
+	etag := ContentETag(&dto.Content{ID: 1, LastModifiedDate: fixedTime})
+
+	tests := []struct {
+		name  string
+		write func(s *Service) error
+	}{
+		{name: "update", write: func(s *Service) error {
+			_, err := s.UpdateContent(context.Background(), 1, dto.Content{Name: "Changed"}, "", etag)
+			return err
+		}},
+		{name: "patch", write: func(s *Service) error {
+			_, err := s.PatchContent(context.Background(), 1, []byte(`{"name":"Changed"}`), "", etag)
+			return err
+		}},
+		{name: "reorder", write: func(s *Service) error {
+			_, err := s.ReorderDetails(context.Background(), 1, []int{}, "", etag)
+			return err
+		}},
+		{name: "transition", write: func(s *Service) error {
+			_, err := s.TransitionContent(context.Background(), 1, string(model.StatusReview), "", etag)
+			return err
+		}},
+		{name: "delete", write: func(s *Service) error {
+			return s.DeleteContent(context.Background(), 1, etag)
+		}},
+		{name: "purge", write: func(s *Service) error {
+			return s.PurgeContent(context.Background(), 1, etag)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := *stale
+			current.LastModifiedDate = fixedTime.Add(time.Second) // Modified after the read
+			repo := &staleReadRepository{
+				MockRepository: &MockRepository{MockedContent: []*model.Content{&current}},
+				stale:          stale,
+			}
+			service := NewContentService(repo, testClock, nil)
+
+			if err := tt.write(service); !errors.Is(err, ErrPreconditionFailed) {
+				t.Errorf("%s error = %v, expected error = %v", tt.name, err, ErrPreconditionFailed)
+			}
+		})
+	}
+}
+
 func TestService_CreateContent(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -142,10 +654,12 @@ func TestService_CreateContent(t *testing.T) {
 			},
 			repoMock: &MockRepository{},
 			expected: &dto.Content{
-				ID:           1,
-				Name:         "Test Name",
-				Description:  "Test Description",
-				CreationDate: fixedTime,
+				ID:               1,
+				Name:             "Test Name",
+				Description:      "Test Description",
+				Status:           "draft",
+				CreationDate:     fixedTime,
+				LastModifiedDate: fixedTime,
 			},
 			expectErr: false,
 		},
@@ -165,9 +679,9 @@ func TestService_CreateContent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewContentService(tt.repoMock, testClock)
+			service := NewContentService(tt.repoMock, testClock, nil)
 
-			result, err := service.CreateContent(tt.input)
+			result, err := service.CreateContent(context.Background(), tt.input, "")
 
 			if (err != nil) != tt.expectErr {
 				t.Errorf("CreateContent() error = %v, expectErr = %v", err, tt.expectErr)
@@ -180,3 +694,452 @@ func TestService_CreateContent(t *testing.T) {
 		})
 	}
 }
+
+func TestService_UpdateContent(t *testing.T) {
+	createdTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		id          int
+		input       dto.Content
+		repoMock    *MockRepository
+		expected    *dto.Content
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "successful update",
+			id:   1,
+			input: dto.Content{
+				Name:        "Updated Name",
+				Description: "Updated Description",
+				Details:     []dto.Details{{ContentType: "image", Value: "https://example.com/a.png"}},
+			},
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{
+					{ID: 1, Name: "Test Name", Description: "Test Description", CreationDate: createdTime,
+						LastModifiedDate: createdTime},
+				},
+				ContentTypeNameToIDMap: map[string]*model.ContentType{"image": {ID: 2, Name: "image"}},
+				ContentTypeIDToNameMap: map[int]*model.ContentType{2: {ID: 2, Name: "image"}},
+			},
+			expected: &dto.Content{
+				ID:               1,
+				Name:             "Updated Name",
+				Description:      "Updated Description",
+				CreationDate:     createdTime,
+				LastModifiedDate: fixedTime,
+				LastModifiedBy:   "editor",
+				Details:          []dto.Details{{ContentType: "image", Value: "https://example.com/a.png"}},
+			},
+			expectErr: false,
+		},
+		{
+			name:  "content not found",
+			id:    2,
+			input: dto.Content{Name: "Updated Name"},
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{{ID: 1, Name: "Test Name"}},
+			},
+			expected:    nil,
+			expectedErr: ErrContentNotFound,
+			expectErr:   true,
+		},
+		{
+			name:  "repository error",
+			id:    1,
+			input: dto.Content{Name: "Updated Name"},
+			repoMock: &MockRepository{
+				MockedError: errors.New("repository error"),
+			},
+			expected:  nil,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(tt.repoMock, testClock, nil)
+
+			result, err := service.UpdateContent(context.Background(), tt.id, tt.input, "editor", "")
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("UpdateContent() error = %v, expectErr = %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("UpdateContent() error = %v, expected error = %v", err, tt.expectedErr)
+				return
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("UpdateContent() got = %v, expected = %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_PatchContent(t *testing.T) {
+	createdTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	newRepoMock := func() *MockRepository {
+		return &MockRepository{
+			MockedContent: []*model.Content{
+				{ID: 1, Name: "Test Name", Description: "Test Description", CreationDate: createdTime,
+					LastModifiedDate: createdTime,
+					Details:          []*model.Details{{ID: 1, ContentID: 1, ContentTypeID: 1, Value: "Test Value"}}},
+			},
+			ContentTypeNameToIDMap: map[string]*model.ContentType{"text": {ID: 1, Name: "text"}},
+			ContentTypeIDToNameMap: map[int]*model.ContentType{1: {ID: 1, Name: "text"}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		id          int
+		patch       string
+		repoMock    *MockRepository
+		expected    *dto.Content
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name:     "patch single field",
+			id:       1,
+			patch:    `{"name":"Patched Name"}`,
+			repoMock: newRepoMock(),
+			expected: &dto.Content{
+				ID:               1,
+				Name:             "Patched Name",
+				Description:      "Test Description",
+				CreationDate:     createdTime,
+				LastModifiedDate: fixedTime,
+				LastModifiedBy:   "editor",
+				Details:          []dto.Details{{ContentType: "text", Value: "Test Value"}},
+			},
+			expectErr: false,
+		},
+		{
+			name:     "remove field and replace details",
+			id:       1,
+			patch:    `{"description":null,"details":[{"content_type":"text","value":"New Value"}]}`,
+			repoMock: newRepoMock(),
+			expected: &dto.Content{
+				ID:               1,
+				Name:             "Test Name",
+				CreationDate:     createdTime,
+				LastModifiedDate: fixedTime,
+				LastModifiedBy:   "editor",
+				Details:          []dto.Details{{ContentType: "text", Value: "New Value"}},
+			},
+			expectErr: false,
+		},
+		{
+			name:     "identity fields cannot be patched",
+			id:       1,
+			patch:    `{"id":99,"creation_date":"2030-01-01T00:00:00Z"}`,
+			repoMock: newRepoMock(),
+			expected: &dto.Content{
+				ID:               1,
+				Name:             "Test Name",
+				Description:      "Test Description",
+				CreationDate:     createdTime,
+				LastModifiedDate: fixedTime,
+				LastModifiedBy:   "editor",
+				Details:          []dto.Details{{ContentType: "text", Value: "Test Value"}},
+			},
+			expectErr: false,
+		},
+		{
+			name:        "invalid patch",
+			id:          1,
+			patch:       `{"name":`,
+			repoMock:    newRepoMock(),
+			expected:    nil,
+			expectedErr: ErrInvalidPatch,
+			expectErr:   true,
+		},
+		{
+			name:        "content not found",
+			id:          2,
+			patch:       `{"name":"Patched Name"}`,
+			repoMock:    newRepoMock(),
+			expected:    nil,
+			expectedErr: ErrContentNotFound,
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(tt.repoMock, testClock, nil)
+
+			result, err := service.PatchContent(context.Background(), tt.id, []byte(tt.patch), "editor", "")
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("PatchContent() error = %v, expectErr = %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("PatchContent() error = %v, expected error = %v", err, tt.expectedErr)
+				return
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("PatchContent() got = %v, expected = %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_ReorderDetails(t *testing.T) {
+	createdTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	newRepoMock := func() *MockRepository {
+		return &MockRepository{
+			MockedContent: []*model.Content{
+				{ID: 1, Name: "Test Name", Status: model.StatusPublished, CreationDate: createdTime,
+					LastModifiedDate: createdTime, Details: []*model.Details{
+						{ID: 1, ContentTypeID: 1, Value: "first", ContentTypeName: "text"},
+						{ID: 2, ContentTypeID: 2, Value: "https://example.com/a.png", ContentTypeName: "image"},
+						{ID: 3, ContentTypeID: 1, Value: "third", ContentTypeName: "text"},
+					}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		id          int
+		order       []int
+		expected    []dto.Details
+		expectedErr error
+	}{
+		{
+			name:  "successful reorder",
+			id:    1,
+			order: []int{2, 0, 1},
+			expected: []dto.Details{
+				{ContentType: "text", Value: "third"},
+				{ContentType: "text", Value: "first"},
+				{ContentType: "image", Value: "https://example.com/a.png"},
+			},
+		},
+		{name: "missing position", id: 1, order: []int{2, 0}, expectedErr: ErrInvalidDetailOrder},
+		{name: "repeated position", id: 1, order: []int{0, 0, 1}, expectedErr: ErrInvalidDetailOrder},
+		{name: "position out of range", id: 1, order: []int{0, 1, 3}, expectedErr: ErrInvalidDetailOrder},
+		{name: "content not found", id: 2, order: []int{}, expectedErr: ErrContentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := newRepoMock()
+			service := NewContentService(repoMock, testClock, nil)
+
+			result, err := service.ReorderDetails(context.Background(), tt.id, tt.order, "editor", "")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ReorderDetails() error = %v, expected error = %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				if repoMock.UpdatedContent != nil {
+					t.Errorf("ReorderDetails() stored content: %v", repoMock.UpdatedContent)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(result.Details, tt.expected) {
+				t.Errorf("ReorderDetails() got = %v, expected = %v", result.Details, tt.expected)
+			}
+			if result.Status != string(model.StatusPublished) || !result.CreationDate.Equal(createdTime) ||
+				!result.LastModifiedDate.Equal(fixedTime) || result.LastModifiedBy != "editor" {
+				t.Errorf("ReorderDetails() got = %+v, expected the content modified by the editor", result)
+			}
+		})
+	}
+}
+
+func TestService_DeleteAndRestoreContent(t *testing.T) {
+	repoMock := &MockRepository{
+		MockedContent: []*model.Content{
+			{ID: 1, Name: "Test Name", Description: "Test Description"},
+		},
+	}
+	service := NewContentService(repoMock, testClock, nil)
+
+	if err := service.DeleteContent(context.Background(), 1, ""); err != nil {
+		t.Fatalf("DeleteContent() unexpected error = %v", err)
+	}
+
+	if _, err := service.GetContentByID(context.Background(), 1); !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("GetContentByID() after delete error = %v, expected error = %v", err, ErrContentNotFound)
+	}
+
+	if err := service.DeleteContent(context.Background(), 1, ""); !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("DeleteContent() twice error = %v, expected error = %v", err, ErrContentNotFound)
+	}
+
+	deleted, err := service.GetDeletedContent(context.Background())
+	if err != nil {
+		t.Fatalf("GetDeletedContent() unexpected error = %v", err)
+	}
+	expectedDeleted := []*dto.Content{
+		{ID: 1, Name: "Test Name", Description: "Test Description", DeletedAt: &fixedTime},
+	}
+	if !reflect.DeepEqual(deleted, expectedDeleted) {
+		t.Errorf("GetDeletedContent() got = %v, expected = %v", deleted, expectedDeleted)
+	}
+
+	restored, err := service.RestoreContent(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("RestoreContent() unexpected error = %v", err)
+	}
+	expectedRestored := &dto.Content{ID: 1, Name: "Test Name", Description: "Test Description"}
+	if !reflect.DeepEqual(restored, expectedRestored) {
+		t.Errorf("RestoreContent() got = %v, expected = %v", restored, expectedRestored)
+	}
+
+	if _, err := service.RestoreContent(context.Background(), 1); !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("RestoreContent() twice error = %v, expected error = %v", err, ErrContentNotFound)
+	}
+}
+
+func TestService_PurgeContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		repoMock    *MockRepository
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "successful purge",
+			id:   1,
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{{ID: 1, Name: "Test Name"}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "content not found",
+			id:   2,
+			repoMock: &MockRepository{
+				MockedContent: []*model.Content{{ID: 1, Name: "Test Name"}},
+			},
+			expectedErr: ErrContentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "repository error",
+			id:   1,
+			repoMock: &MockRepository{
+				MockedError: errors.New("repository error"),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(tt.repoMock, testClock, nil)
+
+			err := service.PurgeContent(context.Background(), tt.id, "")
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("PurgeContent() error = %v, expectErr = %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("PurgeContent() error = %v, expected error = %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestService_SearchContent(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		limit         int
+		repoMock      *MockRepository
+		expected      []*dto.SearchResult
+		expectedQuery string
+		expectErr     bool
+	}{
+		{
+			name:  "successful search",
+			query: "  test  ",
+			repoMock: &MockRepository{
+				SearchResults: []*model.SearchResult{
+					{Content: &model.Content{ID: 2, Name: "Test Name"}, Rank: 0.6, Snippet: "<mark>Test</mark> Name"},
+					{Content: &model.Content{ID: 1, Name: "Other", Description: "test"}, Rank: 0.2, Snippet: "<mark>test</mark>"},
+				},
+			},
+			expected: []*dto.SearchResult{
+				{Content: &dto.Content{ID: 2, Name: "Test Name"}, Score: 0.6, Snippet: "<mark>Test</mark> Name"},
+				{Content: &dto.Content{ID: 1, Name: "Other", Description: "test"}, Score: 0.2, Snippet: "<mark>test</mark>"},
+			},
+			expectedQuery: "test",
+			expectErr:     false,
+		},
+		{
+			name:          "no matches",
+			query:         "missing",
+			repoMock:      &MockRepository{},
+			expected:      []*dto.SearchResult{},
+			expectedQuery: "missing",
+			expectErr:     false,
+		},
+		{
+			name:      "empty query",
+			query:     "   ",
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name:      "limit too large",
+			query:     "test",
+			limit:     MaxPageSize + 1,
+			repoMock:  &MockRepository{},
+			expected:  nil,
+			expectErr: true,
+		},
+		{
+			name:  "repository error",
+			query: "test",
+			repoMock: &MockRepository{
+				MockedError: errors.New("repository error"),
+			},
+			expected:      nil,
+			expectedQuery: "test",
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewContentService(tt.repoMock, testClock, nil)
+
+			result, err := service.SearchContent(context.Background(), tt.query, tt.limit, false)
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("SearchContent() error = %v, expectErr = %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectedQuery != "" && tt.repoMock.LastSearchStatus != model.StatusPublished {
+				t.Errorf("SearchContent() repository status = %q, expected = %q",
+					tt.repoMock.LastSearchStatus, model.StatusPublished)
+			}
+
+			if tt.repoMock.LastSearch != tt.expectedQuery {
+				t.Errorf("SearchContent() repository query = %q, expected = %q", tt.repoMock.LastSearch, tt.expectedQuery)
+			}
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("SearchContent() got = %v, expected = %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/model"
+	"mime"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// mediaTypesByExtension resolves the media types of common image and video file extensions independently of the
+// MIME tables installed on the host. Other extensions fall back to the mime package.
+var mediaTypesByExtension = map[string]string{
+	".avif": "image/avif",
+	".gif":  "image/gif",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".svg":  "image/svg+xml",
+	".webp": "image/webp",
+	".m4v":  "video/x-m4v",
+	".mov":  "video/quicktime",
+	".mp4":  "video/mp4",
+	".ogv":  "video/ogg",
+	".webm": "video/webm",
+}
+
+// validateValue checks a detail value against the rules of its content type, returning a message if it is invalid
+func validateValue(rules model.ValueRules, value string) string {
+	if rules.MaxLength > 0 && utf8.RuneCountInString(value) > rules.MaxLength {
+		return fmt.Sprintf("must be at most %d characters", rules.MaxLength)
+	}
+
+	if rules.Format != model.FormatURL {
+		return ""
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "must be an absolute http or https URL"
+	}
+
+	if len(rules.MimeTypes) == 0 {
+		return ""
+	}
+	mediaType := mediaTypeOf(u.Path)
+	if !slices.ContainsFunc(rules.MimeTypes, func(allowed string) bool { return matchMediaType(allowed, mediaType) }) {
+		return "must link to media of type " + strings.Join(rules.MimeTypes, ", ")
+	}
+
+	return ""
+}
+
+// mediaTypeOf infers the media type of a URL path from its file extension, or returns an empty string
+func mediaTypeOf(p string) string {
+	ext := strings.ToLower(path.Ext(p))
+	if mediaType, ok := mediaTypesByExtension[ext]; ok {
+		return mediaType
+	}
+	mediaType, _, _ := mime.ParseMediaType(mime.TypeByExtension(ext))
+	return mediaType
+}
+
+// matchMediaType reports whether a media type matches an allowed type, which may use a wildcard subtype ("image/*")
+func matchMediaType(allowed, mediaType string) bool {
+	if mediaType == "" {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return allowed == mediaType
+}
+
+// validateRules checks that value rules are consistent before they are stored with a content type
+func validateRules(rules model.ValueRules) error {
+	if rules.MaxLength < 0 {
+		return fmt.Errorf("%w: max_length must not be negative", ErrInvalidContentType)
+	}
+	if rules.Format != "" && rules.Format != model.FormatURL {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidContentType, rules.Format)
+	}
+	if len(rules.MimeTypes) > 0 && rules.Format != model.FormatURL {
+		return fmt.Errorf("%w: mime_types requires the %q format", ErrInvalidContentType, model.FormatURL)
+	}
+	for _, mt := range rules.MimeTypes {
+		typ, subtype, ok := strings.Cut(mt, "/")
+		if !ok || typ == "" || subtype == "" || strings.ContainsAny(mt, " ;") {
+			return fmt.Errorf("%w: invalid media type %q", ErrInvalidContentType, mt)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/apperror"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/model"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateValue(t *testing.T) {
+	text := model.ValueRules{MaxLength: 5}
+	image := model.ValueRules{Format: model.FormatURL, MimeTypes: []string{"image/*"}}
+	png := model.ValueRules{Format: model.FormatURL, MimeTypes: []string{"image/png"}}
+
+	tests := []struct {
+		name      string
+		rules     model.ValueRules
+		value     string
+		expectErr bool
+	}{
+		{name: "no rules", rules: model.ValueRules{}, value: strings.Repeat("a", 100)},
+		{name: "within max length", rules: text, value: "héllo"},
+		{name: "exceeds max length", rules: text, value: "hello!", expectErr: true},
+		{name: "url", rules: model.ValueRules{Format: model.FormatURL}, value: "https://example.com/page"},
+		{name: "relative url", rules: model.ValueRules{Format: model.FormatURL}, value: "/a.png", expectErr: true},
+		{name: "unsupported scheme", rules: image, value: "ftp://example.com/a.png", expectErr: true},
+		{name: "not a url", rules: image, value: "just text", expectErr: true},
+		{name: "wildcard media type", rules: image, value: "https://example.com/a.JPG?size=large"},
+		{name: "media type mismatch", rules: image, value: "https://example.com/a.mp4", expectErr: true},
+		{name: "unknown media type", rules: image, value: "https://example.com/a", expectErr: true},
+		{name: "exact media type", rules: png, value: "https://example.com/a.png"},
+		{name: "exact media type mismatch", rules: png, value: "https://example.com/a.gif", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validateValue(tt.rules, tt.value)
+			if (msg != "") != tt.expectErr {
+				t.Errorf("validateValue() got = %q, expectErr = %v", msg, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestService_CreateContent_Validation(t *testing.T) {
+	publishAt := fixedTime.Add(time.Hour)
+
+	text := &model.ContentType{ID: 1, Name: "text", Rules: model.ValueRules{MaxLength: 10}}
+	image := &model.ContentType{ID: 2, Name: "image",
+		Rules: model.ValueRules{Format: model.FormatURL, MimeTypes: []string{"image/*"}}}
+	repoMock := &MockRepository{
+		ContentTypeNameToIDMap: map[string]*model.ContentType{"text": text, "image": image},
+		ContentTypeIDToNameMap: map[int]*model.ContentType{1: text, 2: image},
+	}
+	service := NewContentService(repoMock, testClock, nil)
+
+	_, err := service.CreateContent(context.Background(), dto.Content{
+		Name:        "Test Name",
+		PublishAt:   &publishAt,
+		UnpublishAt: &fixedTime,
+		Details: []dto.Details{
+			{ContentType: "text", Value: "short"},
+			{ContentType: "text", Value: "far too long for the rules"},
+			{ContentType: "image", Value: "https://example.com/clip.mp4"},
+			{ContentType: "quote", Value: "Unknown"},
+		},
+	}, "")
+
+	var validationErr *apperror.Error
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("CreateContent() error = %v, expected a validation error", err)
+	}
+
+	fields := make([]string, 0)
+	for _, f := range validationErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	expected := []string{"unpublish_at", "details[1].value", "details[2].value", "details[3].content_type"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("CreateContent() invalid fields got = %v, expected = %v", fields, expected)
+	}
+	if repoMock.CreatedContent != nil {
+		t.Errorf("CreateContent() stored invalid content: %v", repoMock.CreatedContent)
+	}
+}
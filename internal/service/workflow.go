@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/logging"
+	"github.com/g-stro/content-management-service/internal/model"
+	"github.com/g-stro/content-management-service/internal/repository"
+	"github.com/g-stro/content-management-service/internal/tracing"
+	"slices"
+)
+
+// StatusAny is the status query value that lists content regardless of its workflow status
+const StatusAny = "any"
+
+// transitions defines the editorial workflow: the statuses content may move to from each status
+var transitions = map[model.ContentStatus][]model.ContentStatus{
+	model.StatusDraft:     {model.StatusReview},
+	model.StatusReview:    {model.StatusDraft, model.StatusPublished},
+	model.StatusPublished: {model.StatusArchived, model.StatusDraft},
+	model.StatusArchived:  {model.StatusDraft},
+}
+
+// scheduleBatchSize is the number of scheduled transitions applied per repository call
+const scheduleBatchSize = 100
+
+// CanTransition reports whether the workflow allows content to move between the two statuses
+func CanTransition(from, to model.ContentStatus) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// TransitionContent moves content to a new workflow status, enforcing the allowed transitions. A non-empty ifMatch
+// makes the transition conditional on the entity tag of the content.
+func (s *Service) TransitionContent(
+	ctx context.Context, id int, status string, author, ifMatch string) (*dto.Content, error) {
+	ctx, span := tracing.Start(ctx, "Service.TransitionContent")
+	defer span.End()
+	span.SetAttribute("content.id", id)
+
+	to, err := parseStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetContentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrContentNotFound
+	}
+	expectedModified, err := expectedVersion(existing, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransition(existing.Status, to) {
+		return nil, fmt.Errorf("%w: cannot move content from %s to %s", ErrInvalidTransition, existing.Status, to)
+	}
+
+	content, err := s.repo.UpdateContentStatus(ctx, id, existing.Status, to, s.clock(), author, expectedModified)
+	if err != nil {
+		if errors.Is(err, repository.ErrModified) {
+			return nil, ErrPreconditionFailed
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			// The content was deleted or changed status since it was read
+			return nil, fmt.Errorf("%w: content status changed concurrently", ErrInvalidTransition)
+		}
+		return nil, err
+	}
+	if to == model.StatusPublished {
+		s.countContent(ctx, s.metrics.published, content)
+	}
+
+	resp, err := s.convertContentModelToDTO(ctx, content)
+	if err != nil {
+		return nil, errors.New("failed to convert model to response DTO")
+	}
+
+	return resp, nil
+}
+
+// RunScheduledTransitions publishes content in review whose publish time has passed and archives published content
+// whose unpublish time has passed, according to the service clock. It returns the number of items transitioned.
+// Both transitions are part of the workflow defined by transitions.
+func (s *Service) RunScheduledTransitions(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "Service.RunScheduledTransitions")
+	defer span.End()
+
+	now := s.clock()
+
+	total := 0
+	for {
+		transitioned, err := s.repo.ApplyScheduledTransitions(ctx, now, scheduleBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, c := range transitioned {
+			logging.FromContext(ctx).Info("applied scheduled transition", "content_id", c.ID, "status", c.Status)
+			if c.Status == model.StatusPublished {
+				s.countContent(ctx, s.metrics.published, c)
+			}
+		}
+		total += len(transitioned)
+
+		if len(transitioned) < scheduleBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// parseStatus converts a status name into a workflow status
+func parseStatus(status string) (model.ContentStatus, error) {
+	s := model.ContentStatus(status)
+	if _, ok := transitions[s]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+	return s, nil
+}
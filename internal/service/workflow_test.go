@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/g-stro/content-management-service/internal/dto"
+	"github.com/g-stro/content-management-service/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from     model.ContentStatus
+		to       model.ContentStatus
+		expected bool
+	}{
+		{from: model.StatusDraft, to: model.StatusReview, expected: true},
+		{from: model.StatusDraft, to: model.StatusPublished, expected: false},
+		{from: model.StatusReview, to: model.StatusPublished, expected: true},
+		{from: model.StatusReview, to: model.StatusDraft, expected: true},
+		{from: model.StatusPublished, to: model.StatusArchived, expected: true},
+		{from: model.StatusPublished, to: model.StatusDraft, expected: true},
+		{from: model.StatusPublished, to: model.StatusReview, expected: false},
+		{from: model.StatusArchived, to: model.StatusDraft, expected: true},
+		{from: model.StatusArchived, to: model.StatusPublished, expected: false},
+		{from: model.StatusDraft, to: model.StatusDraft, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.expected {
+				t.Errorf("CanTransition() got = %v, expected = %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_TransitionContent(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             int
+		status         string
+		expectedStatus string
+		expectedErr    error
+	}{
+		{name: "allowed transition", id: 1, status: "review", expectedStatus: "review"},
+		{name: "skipping review", id: 1, status: "published", expectedErr: ErrInvalidTransition},
+		{name: "unknown status", id: 1, status: "live", expectedErr: ErrInvalidStatus},
+		{name: "content not found", id: 2, status: "review", expectedErr: ErrContentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := &MockRepository{
+				MockedContent: []*model.Content{{ID: 1, Name: "Test Name", Status: model.StatusDraft}},
+			}
+			service := NewContentService(repoMock, testClock, nil)
+
+			result, err := service.TransitionContent(context.Background(), tt.id, tt.status, "editor", "")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("TransitionContent() error = %v, expected error = %v", err, tt.expectedErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if result.Status != tt.expectedStatus || !result.LastModifiedDate.Equal(fixedTime) ||
+				result.LastModifiedBy != "editor" {
+				t.Errorf("TransitionContent() got = %+v", result)
+			}
+		})
+	}
+}
+
+func TestService_GetContent_StatusFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		expected  model.ContentStatus
+		expectErr bool
+	}{
+		{name: "published by default", status: "", expected: model.StatusPublished},
+		{name: "explicit status", status: "draft", expected: model.StatusDraft},
+		{name: "any status", status: StatusAny, expected: ""},
+		{name: "unknown status", status: "live", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := &MockRepository{}
+			service := NewContentService(repoMock, testClock, nil)
+
+			_, err := service.GetContent(context.Background(), dto.ContentQuery{Status: tt.status})
+			if (err != nil) != tt.expectErr {
+				t.Errorf("GetContent() error = %v, expectErr = %v", err, tt.expectErr)
+				return
+			}
+
+			if !tt.expectErr && repoMock.LastFilter.Status != tt.expected {
+				t.Errorf("GetContent() filter status = %q, expected = %q", repoMock.LastFilter.Status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_RunScheduledTransitions(t *testing.T) {
+	past := fixedTime.Add(-time.Minute)
+	future := fixedTime.Add(time.Minute)
+
+	repoMock := &MockRepository{
+		MockedContent: []*model.Content{
+			{ID: 1, Status: model.StatusReview, PublishAt: &past},
+			{ID: 2, Status: model.StatusReview, PublishAt: &future},
+			{ID: 3, Status: model.StatusDraft, PublishAt: &past}, // Must pass review before it can be published
+			{ID: 4, Status: model.StatusPublished, UnpublishAt: &past},
+			{ID: 5, Status: model.StatusPublished, UnpublishAt: &future},
+			{ID: 6, Status: model.StatusReview, PublishAt: &fixedTime},
+		},
+	}
+	service := NewContentService(repoMock, testClock, nil)
+
+	n, err := service.RunScheduledTransitions(context.Background())
+	if err != nil {
+		t.Fatalf("RunScheduledTransitions() unexpected error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("RunScheduledTransitions() got = %d, expected = 3", n)
+	}
+
+	expected := map[int]model.ContentStatus{
+		1: model.StatusPublished,
+		2: model.StatusReview,
+		3: model.StatusDraft,
+		4: model.StatusArchived,
+		5: model.StatusPublished,
+		6: model.StatusPublished,
+	}
+	for _, c := range repoMock.MockedContent {
+		if c.Status != expected[c.ID] {
+			t.Errorf("content %d status = %q, expected = %q", c.ID, c.Status, expected[c.ID])
+		}
+	}
+
+	// Nothing is left to transition on the next run
+	if n, err = service.RunScheduledTransitions(context.Background()); err != nil || n != 0 {
+		t.Errorf("RunScheduledTransitions() second run got = %d, error = %v, expected none", n, err)
+	}
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// WriterExporter writes each span as a line of JSON, for local inspection of traces on stdout or in a file
+type WriterExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{w: w}
+}
+
+// NewFileExporter returns an exporter appending spans to the file at path, which is created if it does not exist
+func NewFileExporter(path string) (*WriterExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	return &WriterExporter{w: f, closer: f}, nil
+}
+
+// exportedSpan is the JSON representation of a span, named after the fields of OpenTelemetry spans
+type exportedSpan struct {
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Name         string         `json:"name"`
+	StartTime    time.Time      `json:"start_time"`
+	EndTime      time.Time      `json:"end_time"`
+	DurationMS   float64        `json:"duration_ms"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Status       string         `json:"status"`
+	Error        string         `json:"error,omitempty"`
+}
+
+func (e *WriterExporter) ExportSpan(span SpanData) {
+	out := exportedSpan{
+		TraceID:    span.SpanContext.TraceID.String(),
+		SpanID:     span.SpanContext.SpanID.String(),
+		Name:       span.Name,
+		StartTime:  span.Start.UTC(),
+		EndTime:    span.End.UTC(),
+		DurationMS: float64(span.End.Sub(span.Start).Microseconds()) / 1000,
+		Attributes: span.Attributes,
+		Status:     "ok",
+	}
+	if span.ParentSpanID != (SpanID{}) {
+		out.ParentSpanID = span.ParentSpanID.String()
+	}
+	if span.Err != nil {
+		out.Status = "error"
+		out.Error = span.Err.Error()
+	}
+
+	line, err := json.Marshal(out)
+	if err != nil {
+		slog.Error("failed to encode span into JSON", "span", span.Name, "error", err)
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.w.Write(append(line, '\n')); err != nil {
+		slog.Error("failed to export span", "span", span.Name, "error", err)
+	}
+}
+
+// Close closes the file of an exporter created by NewFileExporter. It does nothing for other exporters.
+func (e *WriterExporter) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closer.Close()
+}
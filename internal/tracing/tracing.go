@@ -0,0 +1,245 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceparentHeader is the W3C Trace Context header that propagates the trace of a request between services
+const TraceparentHeader = "traceparent"
+
+// TraceID identifies a trace, shared by every span of a request across services
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext is the part of a span that is propagated to its children, in this process or in other services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// Valid reports whether the span context has non-zero trace and span IDs
+func (sc SpanContext) Valid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Traceparent formats the span context as a traceparent header value, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a traceparent header value, reporting whether it is valid. Versions other than 00 are
+// parsed by their first four fields, as the specification requires.
+func ParseTraceparent(s string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if _, err := hex.DecodeString(version); err != nil || len(flags) != 2 {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	if len(traceID) != 32 || strings.ToLower(traceID) != traceID || len(spanID) != 16 ||
+		strings.ToLower(spanID) != spanID {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceID)); err != nil {
+		return SpanContext{}, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(spanID)); err != nil {
+		return SpanContext{}, false
+	}
+	flagBits, err := hex.DecodeString(flags)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flagBits[0]&1 == 1
+	return sc, sc.Valid()
+}
+
+// Extract returns the span context propagated in the traceparent header, reporting whether there is a valid one
+func Extract(header http.Header) (SpanContext, bool) {
+	return ParseTraceparent(header.Get(TraceparentHeader))
+}
+
+// Inject sets the traceparent header to the span of ctx, if there is one
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFromContext(ctx); span != nil {
+		header.Set(TraceparentHeader, span.data.SpanContext.Traceparent())
+	}
+}
+
+// SpanData is a finished span, as passed to exporters
+type SpanData struct {
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanID SpanID // Zero for a trace started by this process
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]any
+	Err          error
+}
+
+// Exporter receives every sampled span once it has ended. Spans are exported on the goroutine that ends them, so
+// implementations must be safe for concurrent use and should return quickly.
+type Exporter interface {
+	ExportSpan(span SpanData)
+}
+
+// Tracer starts root spans and exports the spans of its traces
+type Tracer struct {
+	exporter Exporter
+}
+
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// Start starts the root span of a request in this process. The span continues the trace of parent if it is valid,
+// and starts a new sampled trace otherwise. Spans of traces that the caller did not sample are propagated but not
+// exported.
+func (t *Tracer) Start(ctx context.Context, name string, parent SpanContext) (context.Context, *Span) {
+	span := &Span{tracer: t, data: SpanData{Name: name, Start: time.Now()}}
+	if parent.Valid() {
+		span.data.SpanContext = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+		span.data.ParentSpanID = parent.SpanID
+	} else {
+		span.data.SpanContext = SpanContext{TraceID: newTraceID(), Sampled: true}
+	}
+	span.data.SpanContext.SpanID = newSpanID()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Start starts a child of the span of ctx. Without a span in ctx, tracing is disabled for the operation and Start
+// returns ctx and a nil span, whose methods do nothing.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+
+	span := &Span{tracer: parent.tracer, data: SpanData{Name: name, Start: time.Now()}}
+	span.data.SpanContext = SpanContext{
+		TraceID: parent.data.SpanContext.TraceID,
+		SpanID:  newSpanID(),
+		Sampled: parent.data.SpanContext.Sampled,
+	}
+	span.data.ParentSpanID = parent.data.SpanContext.SpanID
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the current span of ctx, or nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Span is a timed operation within a trace. A nil Span discards all calls, so callers need not check whether
+// tracing is enabled.
+type Span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the propagated identity of the span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// SetAttribute records a key-value pair describing the operation, replacing any earlier value of the key. Attributes
+// set after the span has ended are ignored.
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return // The attributes may be read by the exporter
+	}
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]any)
+	}
+	s.data.Attributes[key] = value
+}
+
+// SetError marks the operation as failed. A nil error is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Err = err
+}
+
+// End finishes the span and exports it if its trace is sampled. Calls after the first do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if data.SpanContext.Sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.ExportSpan(data)
+	}
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for id == (TraceID{}) {
+		binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for id == (SpanID{}) {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
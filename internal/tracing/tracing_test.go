@@ -0,0 +1,157 @@
+//go:build !integration
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// recordingExporter keeps the exported spans in memory
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *recordingExporter) ExportSpan(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		expectedValid bool
+		expected      string
+	}{
+		{name: "sampled", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expectedValid: true,
+			expected: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "not sampled", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", expectedValid: true,
+			expected: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+		{name: "future version with extra fields", header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-03-x",
+			expectedValid: true, expected: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "empty", header: ""},
+		{name: "invalid version", header: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "extra fields in version 00", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-x"},
+		{name: "zero trace ID", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "zero span ID", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "uppercase", header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "short trace ID", header: "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01"},
+		{name: "not hex", header: "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, valid := ParseTraceparent(tt.header)
+			if valid != tt.expectedValid {
+				t.Fatalf("ParseTraceparent() valid got = %v, expected = %v", valid, tt.expectedValid)
+			}
+			if valid && sc.Traceparent() != tt.expected {
+				t.Errorf("Traceparent() got = %s, expected = %s", sc.Traceparent(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestTracer_Start(t *testing.T) {
+	parent, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	unsampled, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	tests := []struct {
+		name          string
+		parent        SpanContext
+		expectedSpans int
+	}{
+		{name: "new trace", expectedSpans: 2},
+		{name: "remote parent", parent: parent, expectedSpans: 2},
+		{name: "remote parent not sampled", parent: unsampled, expectedSpans: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &recordingExporter{}
+			ctx, root := NewTracer(exporter).Start(context.Background(), "root", tt.parent)
+			_, child := Start(ctx, "child")
+			child.SetAttribute("content.id", 1)
+			child.SetError(errors.New("query failed"))
+			child.End()
+			root.End()
+			root.End() // Ending twice exports once
+
+			if len(exporter.spans) != tt.expectedSpans {
+				t.Fatalf("exported spans got = %d, expected = %d", len(exporter.spans), tt.expectedSpans)
+			}
+			if !root.SpanContext().Valid() || child.SpanContext().TraceID != root.SpanContext().TraceID {
+				t.Errorf("span contexts got = %v and %v, expected a valid trace shared by both",
+					root.SpanContext(), child.SpanContext())
+			}
+			if tt.parent.Valid() && root.SpanContext().TraceID != tt.parent.TraceID {
+				t.Errorf("trace ID got = %s, expected = %s", root.SpanContext().TraceID, tt.parent.TraceID)
+			}
+			if tt.expectedSpans == 0 {
+				return
+			}
+
+			exportedChild, exportedRoot := exporter.spans[0], exporter.spans[1]
+			if exportedChild.ParentSpanID != exportedRoot.SpanContext.SpanID {
+				t.Errorf("child parent span ID got = %s, expected = %s", exportedChild.ParentSpanID,
+					exportedRoot.SpanContext.SpanID)
+			}
+			if exportedRoot.ParentSpanID != tt.parent.SpanID {
+				t.Errorf("root parent span ID got = %s, expected = %s", exportedRoot.ParentSpanID, tt.parent.SpanID)
+			}
+			if exportedChild.Attributes["content.id"] != 1 || exportedChild.Err == nil {
+				t.Errorf("child span got = %+v, expected its attribute and error", exportedChild)
+			}
+		})
+	}
+}
+
+func TestStart_WithoutSpan(t *testing.T) {
+	ctx := context.Background()
+	got, span := Start(ctx, "untraced")
+	if span != nil || got != ctx {
+		t.Fatalf("Start() got = %v, expected a nil span and the same context", span)
+	}
+
+	// Must not panic
+	span.SetAttribute("content.id", 1)
+	span.SetError(errors.New("query failed"))
+	span.End()
+}
+
+func TestWriterExporter(t *testing.T) {
+	var buf bytes.Buffer
+	ctx, root := NewTracer(NewWriterExporter(&buf)).Start(context.Background(), "GET /content", SpanContext{})
+	_, child := Start(ctx, "repository.ListContent")
+	child.SetAttribute("db.rows", 2)
+	child.SetError(errors.New("query failed"))
+	child.End()
+	root.End()
+
+	var spans []map[string]any
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var span map[string]any
+		if err := decoder.Decode(&span); err != nil {
+			t.Fatalf("failed to decode span: %v", err)
+		}
+		spans = append(spans, span)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("exported spans got = %d, expected = 2", len(spans))
+	}
+	if spans[0]["name"] != "repository.ListContent" || spans[0]["status"] != "error" ||
+		spans[0]["error"] != "query failed" || spans[0]["parent_span_id"] != root.SpanContext().SpanID.String() {
+		t.Errorf("child span got = %v", spans[0])
+	}
+	if _, ok := spans[1]["parent_span_id"]; ok || spans[1]["status"] != "ok" {
+		t.Errorf("root span got = %v, expected an ok span without a parent", spans[1])
+	}
+}